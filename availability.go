@@ -0,0 +1,151 @@
+package cmoresearch
+
+import "time"
+
+// AvailabilityContext describes where, on what and by whom a hit is to be
+// played.
+type AvailabilityContext struct {
+	// Site is the site the hit is played on. If empty, any site matches.
+	Site Site
+
+	// DeviceType is the device the hit is played on. If empty, any device
+	// type matches.
+	DeviceType DeviceType
+
+	// Products are the products and product groups the user has access to.
+	Products []string
+
+	// Now is the time of playback. If zero, the current time is used.
+	Now time.Time
+}
+
+// UnavailableReason is the reason a hit is not available.
+type UnavailableReason string
+
+// Reasons for a hit not being available, from the least to the most specific.
+const (
+	ReasonNoEvents       UnavailableReason = "no_events"
+	ReasonWrongSite      UnavailableReason = "wrong_site"
+	ReasonWrongDevice    UnavailableReason = "wrong_device"
+	ReasonMissingProduct UnavailableReason = "missing_product"
+	ReasonNotPublished   UnavailableReason = "not_published"
+	ReasonExpired        UnavailableReason = "expired"
+	ReasonNotStarted     UnavailableReason = "not_started"
+)
+
+var reasonRank = map[UnavailableReason]int{
+	ReasonNoEvents:       0,
+	ReasonWrongSite:      1,
+	ReasonWrongDevice:    2,
+	ReasonMissingProduct: 3,
+	ReasonNotPublished:   4,
+	ReasonExpired:        5,
+	ReasonNotStarted:     6,
+}
+
+// Availability is the result of IsAvailable.
+type Availability struct {
+	Available bool
+
+	// Event is the event making the hit available or, if unavailable, the
+	// event that came closest.
+	Event *Event
+
+	// Reason is why the hit is unavailable, from the event that came
+	// closest to making it available.
+	Reason UnavailableReason
+
+	// NextStart is the start of the next window in which the hit will be
+	// available in the given context, or zero if there is none.
+	NextStart time.Time
+}
+
+// IsAvailable evaluates the events of hit to tell whether it is playable in the
+// given context.
+func IsAvailable(hit Hit, ac AvailabilityContext) Availability {
+	now := ac.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	events := hit.Subset().Events
+
+	av := Availability{Reason: ReasonNoEvents}
+
+	for i := range events {
+		e := &events[i]
+
+		reason, start := e.unavailableReason(ac, now)
+
+		if reason == "" {
+			return Availability{Available: true, Event: e}
+		}
+
+		if !start.IsZero() && (av.NextStart.IsZero() || start.Before(av.NextStart)) {
+			av.NextStart = start
+		}
+
+		if av.Event == nil || reasonRank[reason] > reasonRank[av.Reason] {
+			av.Event = e
+			av.Reason = reason
+		}
+	}
+
+	return av
+}
+
+// unavailableReason returns why e does not make its hit available in the given
+// context, or an empty string if it does. If e is only held back by time, it
+// also returns when it will make its hit available.
+func (e *Event) unavailableReason(ac AvailabilityContext, now time.Time) (UnavailableReason, time.Time) {
+	if ac.Site != "" && e.Site != string(ac.Site) {
+		return ReasonWrongSite, time.Time{}
+	}
+
+	if ac.DeviceType != "" && len(e.DeviceTypes) > 0 && !contains(e.DeviceTypes, string(ac.DeviceType)) {
+		return ReasonWrongDevice, time.Time{}
+	}
+
+	if len(e.Products) > 0 || len(e.ProductGroups) > 0 {
+		entitled := false
+		for _, p := range ac.Products {
+			if contains(e.Products, p) || contains(e.ProductGroups, p) {
+				entitled = true
+				break
+			}
+		}
+		if !entitled {
+			return ReasonMissingProduct, time.Time{}
+		}
+	}
+
+	if !e.LivePublished && !e.OnDemandPublished {
+		return ReasonNotPublished, time.Time{}
+	}
+
+	if !e.EndTime.IsZero() && !now.Before(e.EndTime) {
+		return ReasonExpired, time.Time{}
+	}
+
+	// The event makes its hit available once both published and started,
+	// unless it ends before that.
+	start := e.StartTime
+	if e.PublishTime.After(start) {
+		start = e.PublishTime
+	}
+	if !e.EndTime.IsZero() && !start.Before(e.EndTime) {
+		start = time.Time{}
+	}
+
+	// An event published in the future is not published yet, however its
+	// flags are set.
+	if now.Before(e.PublishTime) {
+		return ReasonNotPublished, start
+	}
+
+	if now.Before(e.StartTime) {
+		return ReasonNotStarted, start
+	}
+
+	return "", time.Time{}
+}
@@ -0,0 +1,107 @@
+package cmoresearch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsAvailable(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	event := func(modify func(e *Event)) Event {
+		e := Event{
+			Site:              "cmore.se",
+			DeviceTypes:       []string{"tve_web", "tve_mobile"},
+			Products:          []string{"cmore_premium"},
+			ProductGroups:     []string{"premium"},
+			StartTime:         now.Add(-time.Hour),
+			EndTime:           now.Add(time.Hour),
+			OnDemandPublished: true,
+		}
+		if modify != nil {
+			modify(&e)
+		}
+		return e
+	}
+
+	ac := AvailabilityContext{
+		Site:       SiteSE,
+		DeviceType: DeviceTypeWeb,
+		Products:   []string{"premium"},
+		Now:        now,
+	}
+
+	for _, tc := range []struct {
+		description string
+		events      []Event
+		available   bool
+		reason      UnavailableReason
+		nextStart   time.Time
+	}{
+		{"Available", []Event{event(nil)}, true, "", time.Time{}},
+		{"NoEvents", nil, false, ReasonNoEvents, time.Time{}},
+		{"WrongSite", []Event{event(func(e *Event) { e.Site = "cmore.no" })}, false, ReasonWrongSite, time.Time{}},
+		{"WrongDevice", []Event{event(func(e *Event) { e.DeviceTypes = []string{"tve_smarttv"} })}, false, ReasonWrongDevice, time.Time{}},
+		{"AnyDevice", []Event{event(func(e *Event) { e.DeviceTypes = nil })}, true, "", time.Time{}},
+		{"MissingProduct", []Event{event(func(e *Event) { e.ProductGroups = nil })}, false, ReasonMissingProduct, time.Time{}},
+		{"Free", []Event{event(func(e *Event) { e.Products, e.ProductGroups = nil, nil })}, true, "", time.Time{}},
+		{"NotPublished", []Event{event(func(e *Event) { e.OnDemandPublished = false })}, false, ReasonNotPublished, time.Time{}},
+		{"PublishedLater", []Event{event(func(e *Event) { e.PublishTime = now.Add(time.Minute) })}, false, ReasonNotPublished, now.Add(time.Minute)},
+		{
+			"PublishedBeforeStart",
+			[]Event{event(func(e *Event) { e.PublishTime, e.StartTime = now.Add(time.Minute), now.Add(30*time.Minute) })},
+			false, ReasonNotPublished, now.Add(30 * time.Minute),
+		},
+		{"PublishedAfterEnd", []Event{event(func(e *Event) { e.PublishTime = now.Add(2 * time.Hour) })}, false, ReasonNotPublished, time.Time{}},
+		{"PublishedEarlier", []Event{event(func(e *Event) { e.PublishTime = now.Add(-time.Minute) })}, true, "", time.Time{}},
+		{"LivePublished", []Event{event(func(e *Event) { e.OnDemandPublished, e.LivePublished = false, true })}, true, "", time.Time{}},
+		{"Expired", []Event{event(func(e *Event) { e.EndTime = now })}, false, ReasonExpired, time.Time{}},
+		{"NoEndTime", []Event{event(func(e *Event) { e.EndTime = time.Time{} })}, true, "", time.Time{}},
+		{
+			"NotStarted",
+			[]Event{
+				event(func(e *Event) { e.StartTime = now.Add(48 * time.Hour); e.EndTime = now.Add(72 * time.Hour) }),
+				event(func(e *Event) { e.StartTime = now.Add(24 * time.Hour); e.EndTime = now.Add(48 * time.Hour) }),
+				event(func(e *Event) { e.EndTime = now.Add(-time.Minute) }),
+			},
+			false, ReasonNotStarted, now.Add(24 * time.Hour),
+		},
+		{
+			"ClosestReason",
+			[]Event{
+				event(func(e *Event) { e.Site = "cmore.dk" }),
+				event(func(e *Event) { e.ProductGroups = nil }),
+				event(func(e *Event) { e.DeviceTypes = []string{"tve_appletv"} }),
+			},
+			false, ReasonMissingProduct, time.Time{},
+		},
+		{
+			"SecondEventAvailable",
+			[]Event{
+				event(func(e *Event) { e.Site = "cmore.dk" }),
+				event(func(e *Event) { e.ProductGroups = []string{"sport", "premium"} }),
+			},
+			true, "", time.Time{},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			av := IsAvailable(&Asset{Events: tc.events}, ac)
+
+			if got, want := av.Available, tc.available; got != want {
+				t.Errorf("av.Available = %t, want %t", got, want)
+			}
+
+			if got, want := av.Reason, tc.reason; got != want {
+				t.Errorf("av.Reason = %q, want %q", got, want)
+			}
+
+			if got, want := av.NextStart, tc.nextStart; !got.Equal(want) {
+				t.Errorf("av.NextStart = %s, want %s", got, want)
+			}
+
+			if len(tc.events) > 0 && av.Event == nil {
+				t.Error("av.Event is nil")
+			}
+		})
+	}
+}
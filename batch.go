@@ -0,0 +1,188 @@
+package cmoresearch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// batchMaxIDs is the maximum number of IDs looked up per request.
+	batchMaxIDs = 100
+
+	// batchMaxIDsLength is the maximum length of the escaped, comma
+	// separated IDs of a request, keeping URLs well below common limits.
+	batchMaxIDsLength = 2000
+
+	// batchWorkers is the number of requests made concurrently by a batch
+	// lookup.
+	batchWorkers = 4
+
+	// seriesMaxHits is the maximum number of hits searched through for the
+	// series of a brand, ten pages of the SearchAll default page size.
+	seriesMaxHits = 10 * defaultIteratorPageSize
+)
+
+// GetAssets looks up the assets with the given video IDs. The IDs are split
+// into chunks, keeping the request URLs short enough, which are requested
+// concurrently. The assets found are returned in the order of videoIDs, along
+// with the IDs not found, in the same order. Duplicate and empty IDs are
+// ignored.
+func (c *Client) GetAssets(ctx context.Context, videoIDs []string, options ...func(r *http.Request)) ([]*Asset, []string, error) {
+	videoIDs = uniqueIDs(videoIDs)
+
+	found, notFound, err := c.getBatch(ctx, videoIDs, chunkIDs(videoIDs, batchMaxIDs, batchMaxIDsLength), "video_ids", 0, func(h Hit) string {
+		if a, ok := h.(*Asset); ok {
+			return a.VideoID
+		}
+		return ""
+	}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assets := make([]*Asset, len(found))
+	for i, h := range found {
+		assets[i] = h.(*Asset)
+	}
+
+	return assets, notFound, nil
+}
+
+// GetSeries looks up the series with the given brand IDs. Since the brand_id
+// parameter takes a single ID, and also matches the assets of the brand, each
+// brand ID is searched for separately, concurrently, until its series is
+// found. See GetAssets.
+//
+// Finding a series may take several pages of full assets of its brand. A brand
+// is reported as not found after its first 1000 hits, so brands without a
+// series cost at most ten requests each.
+func (c *Client) GetSeries(ctx context.Context, brandIDs []string, options ...func(r *http.Request)) ([]*Series, []string, error) {
+	brandIDs = uniqueIDs(brandIDs)
+
+	chunks := make([][]string, len(brandIDs))
+	for i, id := range brandIDs {
+		chunks[i] = []string{id}
+	}
+
+	found, notFound, err := c.getBatch(ctx, brandIDs, chunks, "brand_id", seriesMaxHits, func(h Hit) string {
+		if s, ok := h.(*Series); ok {
+			return s.BrandID
+		}
+		return ""
+	}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	series := make([]*Series, len(found))
+	for i, h := range found {
+		series[i] = h.(*Series)
+	}
+
+	return series, notFound, nil
+}
+
+// getBatch looks up the hits with the given IDs by searching for each chunk of
+// IDs, comma separated in the param parameter. The ID of a hit is given by id,
+// which returns an empty string for hits of other types. The search for a
+// chunk stops once every ID in it has been found or, if maxHits is positive,
+// after maxHits hits.
+func (c *Client) getBatch(ctx context.Context, ids []string, chunks [][]string, param string, maxHits int, id func(Hit) string, options []func(*http.Request)) ([]Hit, []string, error) {
+	var (
+		mu   sync.Mutex
+		hits = make(map[string]Hit, len(ids))
+	)
+
+	err := forEach(ctx, len(chunks), batchWorkers, func(ctx context.Context, i int) error {
+		chunk := chunks[i]
+
+		q := url.Values{param: {strings.Join(chunk, ",")}}
+
+		it := c.SearchAll(ctx, q, options...)
+
+		for n, remaining := 0, len(chunk); remaining > 0 && (maxHits <= 0 || n < maxHits) && it.Next(); n++ {
+			hitID := id(it.Hit())
+			if hitID == "" || !contains(chunk, hitID) {
+				continue
+			}
+
+			mu.Lock()
+			if _, ok := hits[hitID]; !ok {
+				hits[hitID] = it.Hit()
+				remaining--
+			}
+			mu.Unlock()
+		}
+
+		return it.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		found    = make([]Hit, 0, len(hits))
+		notFound []string
+	)
+
+	for _, id := range ids {
+		if h, ok := hits[id]; ok {
+			found = append(found, h)
+		} else {
+			notFound = append(notFound, id)
+		}
+	}
+
+	return found, notFound, nil
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+
+	return unique
+}
+
+// chunkIDs splits ids into chunks of at most maxIDs IDs, with a query escaped,
+// comma separated length of at most maxLength. An ID longer than maxLength
+// gets a chunk of its own.
+func chunkIDs(ids []string, maxIDs, maxLength int) [][]string {
+	var (
+		chunks [][]string
+		chunk  []string
+		length int
+	)
+
+	for _, id := range ids {
+		l := len(url.QueryEscape(id))
+		if len(chunk) > 0 {
+			l += len(url.QueryEscape(","))
+		}
+
+		if len(chunk) > 0 && (len(chunk) == maxIDs || length+l > maxLength) {
+			chunks = append(chunks, chunk)
+			chunk, length = nil, 0
+			l = len(url.QueryEscape(id))
+		}
+
+		chunk = append(chunk, id)
+		length += l
+	}
+
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
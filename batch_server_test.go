@@ -0,0 +1,70 @@
+package cmoresearch_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	cmoresearch "github.com/TV4/cmoresearch-go"
+	"github.com/TV4/cmoresearch-go/cmoresearchtest"
+)
+
+func TestGetSeries(t *testing.T) {
+	srv := cmoresearchtest.NewServer()
+	defer srv.Close()
+
+	for _, brandID := range []string{"1", "2", "4"} {
+		for e := 1; e <= 150; e++ {
+			srv.Add(&cmoresearch.Asset{Type: "episode", VideoID: brandID + "-" + strconv.Itoa(e), Brand: cmoresearch.Brand{ID: brandID}})
+		}
+		srv.Add(&cmoresearch.Series{Type: "series", BrandID: brandID})
+	}
+
+	srv.Add(&cmoresearch.Asset{Type: "episode", VideoID: "3-1", Brand: cmoresearch.Brand{ID: "3"}})
+
+	series, notFound, err := srv.Client().GetSeries(context.Background(), []string{"4", "3", "1", "2", "1"})
+	if err != nil {
+		t.Fatalf("GetSeries: unexpected error %v", err)
+	}
+
+	var ids []string
+	for _, s := range series {
+		ids = append(ids, s.BrandID)
+	}
+
+	if got, want := fmt.Sprint(ids), "[4 1 2]"; got != want {
+		t.Errorf("series = %s, want %s", got, want)
+	}
+
+	if got, want := fmt.Sprint(notFound), "[3]"; got != want {
+		t.Errorf("notFound = %s, want %s", got, want)
+	}
+}
+
+func TestGetAssets_Server(t *testing.T) {
+	srv := cmoresearchtest.NewServer()
+	defer srv.Close()
+
+	var ids []string
+	for i := 1; i <= 120; i++ {
+		id := strconv.Itoa(i)
+		ids = append(ids, id)
+		if i != 50 {
+			srv.Add(&cmoresearch.Asset{Type: "movie", VideoID: id})
+		}
+	}
+
+	assets, notFound, err := srv.Client().GetAssets(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetAssets: unexpected error %v", err)
+	}
+
+	if got, want := len(assets), 119; got != want {
+		t.Errorf("len(assets) = %d, want %d", got, want)
+	}
+
+	if got, want := fmt.Sprint(notFound), "[50]"; got != want {
+		t.Errorf("notFound = %s, want %s", got, want)
+	}
+}
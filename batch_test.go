@@ -0,0 +1,174 @@
+package cmoresearch
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestChunkIDs(t *testing.T) {
+	for n, tc := range []struct {
+		ids       []string
+		maxIDs    int
+		maxLength int
+		want      string
+	}{
+		{nil, 2, 100, "[]"},
+		{[]string{"1", "2", "3"}, 2, 100, "[[1 2] [3]]"},
+		{[]string{"1", "2", "3"}, 10, 7, "[[1 2] [3]]"},
+		{[]string{"1", "2", "3"}, 10, 8, "[[1 2] [3]]"},
+		{[]string{"1", "2", "3"}, 10, 9, "[[1 2 3]]"},
+		{[]string{"a b", "c"}, 10, 4, "[[a b] [c]]"},
+		{[]string{"123456", "1"}, 10, 3, "[[123456] [1]]"},
+	} {
+		if got := fmt.Sprint(chunkIDs(tc.ids, tc.maxIDs, tc.maxLength)); got != tc.want {
+			t.Errorf("[%d] chunkIDs = %s, want %s", n, got, tc.want)
+		}
+	}
+}
+
+// catalogTransport serves the assets with the requested video IDs, recording
+// the number of IDs per request.
+func catalogTransport(mu *sync.Mutex, chunkSizes *[]int, missing map[string]bool) mockTransport {
+	return func(r *http.Request) (*http.Response, error) {
+		q := r.URL.Query()
+
+		ids := strings.Split(q.Get("video_ids"), ",")
+
+		mu.Lock()
+		*chunkSizes = append(*chunkSizes, len(ids))
+		mu.Unlock()
+
+		var hits []string
+		for i := len(ids) - 1; i >= 0; i-- {
+			if !missing[ids[i]] {
+				hits = append(hits, fmt.Sprintf(`{"type":"movie","video_id":%q}`, ids[i]))
+			}
+		}
+
+		body := fmt.Sprintf(`{"total_hits":%d,"assets":[%s]}`, len(hits), strings.Join(hits, ","))
+
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+}
+
+func TestGetAssets(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		chunkSizes []int
+	)
+
+	mockT := catalogTransport(&mu, &chunkSizes, map[string]bool{"17": true, "240": true})
+
+	c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+	var ids []string
+	for i := 250; i > 0; i-- {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	ids = append(ids, "", "3")
+
+	assets, notFound, err := c.GetAssets(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetAssets: unexpected error %v", err)
+	}
+
+	if got, want := len(assets), 248; got != want {
+		t.Fatalf("len(assets) = %d, want %d", got, want)
+	}
+
+	for i, a := range assets {
+		want := 250 - i
+		if i >= 10 {
+			want--
+		}
+		if i >= 232 {
+			want--
+		}
+		if got := a.VideoID; got != strconv.Itoa(want) {
+			t.Fatalf("assets[%d].VideoID = %q, want %d", i, got, want)
+		}
+	}
+
+	if got, want := fmt.Sprint(notFound), "[240 17]"; got != want {
+		t.Errorf("notFound = %s, want %s", got, want)
+	}
+
+	sort.Ints(chunkSizes)
+
+	if got, want := fmt.Sprint(chunkSizes), "[50 100 100]"; got != want {
+		t.Errorf("chunkSizes = %s, want %s", got, want)
+	}
+}
+
+func TestGetAssets_Error(t *testing.T) {
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"code":400,"message":"Invalid parameters: video_ids"}`)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusBadRequest,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+
+	c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+	_, _, err := c.GetAssets(context.Background(), []string{"1", "2"})
+
+	if _, ok := err.(*APIError); !ok {
+		t.Errorf("err = %v, want *APIError", err)
+	}
+}
+
+func TestGetSeries_MaxHits(t *testing.T) {
+	var requests int32
+
+	// Every page is full of episodes of the brand, which has no series.
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		hits := make([]string, defaultIteratorPageSize)
+		for i := range hits {
+			hits[i] = fmt.Sprintf(`{"type":"episode","video_id":"%d-%d"}`, page, i)
+		}
+
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":100000,"assets":[` + strings.Join(hits, ",") + `]}`)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+
+	c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+	series, notFound, err := c.GetSeries(context.Background(), []string{"1"})
+	if err != nil {
+		t.Fatalf("GetSeries: unexpected error %v", err)
+	}
+
+	if len(series) != 0 || fmt.Sprint(notFound) != "[1]" {
+		t.Errorf("GetSeries = %v, %v, want [], [1]", series, notFound)
+	}
+
+	if got, want := atomic.LoadInt32(&requests), int32(seriesMaxHits/defaultIteratorPageSize); got != want {
+		t.Errorf("requests = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,245 @@
+package cmoresearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a search fails fast because the circuit
+// breaker of the client is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails all requests fast with ErrCircuitOpen.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of probe requests through to
+	// find out whether the search service has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerSettings configures the circuit breaker of a client. Network
+// errors and HTTP 5xx responses count as failures.
+type CircuitBreakerSettings struct {
+	// FailureRatio is the ratio of failed requests at which the circuit
+	// opens. Defaults to 0.5.
+	FailureRatio float64
+
+	// MinRequests is the number of requests needed within Window before the
+	// failure ratio is considered.
+	MinRequests int
+
+	// Window is the period over which requests are counted while the circuit
+	// is closed. If zero, counts are never reset while closed.
+	Window time.Duration
+
+	// OpenTimeout is how long the circuit stays open before half-opening.
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of successful probe requests needed to
+	// close a half-open circuit. A single failed probe opens it again.
+	HalfOpenProbes int
+
+	// OnStateChange, if set, is called whenever the circuit changes state.
+	OnStateChange func(from, to CircuitState)
+}
+
+// SetCircuitBreaker is an option to set a circuit breaker when creating a new
+// client, making searches fail fast with ErrCircuitOpen while the search
+// service is failing.
+func SetCircuitBreaker(s CircuitBreakerSettings) func(*Client) {
+	return func(c *Client) {
+		if s.FailureRatio <= 0 {
+			s.FailureRatio = 0.5
+		}
+		if s.MinRequests < 1 {
+			s.MinRequests = 1
+		}
+		if s.HalfOpenProbes < 1 {
+			s.HalfOpenProbes = 1
+		}
+		c.breaker = &circuitBreaker{settings: s, windowStart: time.Now()}
+	}
+}
+
+// outcome is the outcome of a request, as counted by the circuit breaker.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+
+	// outcomeIgnored is a request that says nothing about the health of
+	// the search service, e.g. one canceled by the caller. It is not
+	// counted, but frees its probe slot when the circuit is half-open.
+	outcomeIgnored
+)
+
+type circuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  uint64
+	windowStart time.Time
+	openedAt    time.Time
+	requests    int
+	failures    int
+	probes      int
+	successes   int
+}
+
+// allow returns ErrCircuitOpen if a request may not be sent. Otherwise the
+// returned function must be called with the outcome of the request.
+func (cb *circuitBreaker) allow(now time.Time) (func(outcome), error) {
+	cb.mu.Lock()
+
+	var changed func()
+
+	if cb.state == CircuitOpen && now.Sub(cb.openedAt) >= cb.settings.OpenTimeout {
+		changed = cb.setState(CircuitHalfOpen, now)
+	}
+
+	var err error
+
+	switch cb.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.probes >= cb.settings.HalfOpenProbes {
+			err = ErrCircuitOpen
+		} else {
+			cb.probes++
+		}
+	}
+
+	generation := cb.generation
+	cb.mu.Unlock()
+
+	if changed != nil {
+		changed()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(o outcome) { cb.done(generation, o, time.Now()) }, nil
+}
+
+func (cb *circuitBreaker) done(generation uint64, o outcome, now time.Time) {
+	cb.mu.Lock()
+
+	var changed func()
+
+	failed := o == outcomeFailure
+
+	if generation == cb.generation {
+		switch {
+		case o == outcomeIgnored:
+			if cb.state == CircuitHalfOpen {
+				cb.probes--
+			}
+		case cb.state == CircuitClosed:
+			if cb.settings.Window > 0 && now.Sub(cb.windowStart) >= cb.settings.Window {
+				cb.windowStart = now
+				cb.requests, cb.failures = 0, 0
+			}
+
+			cb.requests++
+			if failed {
+				cb.failures++
+			}
+
+			if cb.failures > 0 && cb.requests >= cb.settings.MinRequests &&
+				float64(cb.failures)/float64(cb.requests) >= cb.settings.FailureRatio {
+				changed = cb.setState(CircuitOpen, now)
+			}
+		case cb.state == CircuitHalfOpen:
+			if failed {
+				changed = cb.setState(CircuitOpen, now)
+				break
+			}
+
+			cb.successes++
+			if cb.successes >= cb.settings.HalfOpenProbes {
+				changed = cb.setState(CircuitClosed, now)
+			}
+		}
+	}
+
+	cb.mu.Unlock()
+
+	if changed != nil {
+		changed()
+	}
+}
+
+// setState must be called with cb.mu held. It returns a function notifying
+// OnStateChange, to be called once cb.mu is released.
+func (cb *circuitBreaker) setState(state CircuitState, now time.Time) func() {
+	from := cb.state
+
+	cb.state = state
+	cb.generation++
+	cb.requests, cb.failures = 0, 0
+	cb.probes, cb.successes = 0, 0
+	cb.windowStart = now
+
+	if state == CircuitOpen {
+		cb.openedAt = now
+	}
+
+	if cb.settings.OnStateChange == nil {
+		return nil
+	}
+
+	return func() { cb.settings.OnStateChange(from, state) }
+}
+
+// CircuitState returns the state of the circuit breaker of the client. A client
+// without a circuit breaker is always CircuitClosed.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+
+	return c.breaker.state
+}
+
+// requestOutcome returns the outcome of a request for the circuit breaker.
+// Requests canceled by the caller are ignored.
+func requestOutcome(ctx context.Context, resp *http.Response, err error) outcome {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return outcomeIgnored
+	case err != nil, resp.StatusCode >= 500:
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
@@ -0,0 +1,232 @@
+package cmoresearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var transitions []string
+
+	cb := &circuitBreaker{
+		settings: CircuitBreakerSettings{
+			FailureRatio:   0.5,
+			MinRequests:    4,
+			OpenTimeout:    time.Minute,
+			HalfOpenProbes: 2,
+			OnStateChange: func(from, to CircuitState) {
+				transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+			},
+		},
+	}
+
+	now := time.Now()
+
+	request := func(failed bool) error {
+		done, err := cb.allow(now)
+		if err != nil {
+			return err
+		}
+		if failed {
+			done(outcomeFailure)
+		} else {
+			done(outcomeSuccess)
+		}
+		return nil
+	}
+
+	for i, failed := range []bool{true, false, true} {
+		if err := request(failed); err != nil {
+			t.Fatalf("[%d] unexpected error: %v", i, err)
+		}
+	}
+
+	if got, want := cb.state, CircuitClosed; got != want {
+		t.Fatalf("state below MinRequests = %s, want %s", got, want)
+	}
+
+	if err := request(false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := cb.state, CircuitOpen; got != want {
+		t.Fatalf("state = %s, want %s", got, want)
+	}
+
+	if got, want := request(false), ErrCircuitOpen; got != want {
+		t.Fatalf("open: err = %v, want %v", got, want)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	first, err := cb.allow(now)
+	if err != nil {
+		t.Fatalf("half-open: unexpected error: %v", err)
+	}
+
+	second, err := cb.allow(now)
+	if err != nil {
+		t.Fatalf("half-open: unexpected error: %v", err)
+	}
+
+	if _, err := cb.allow(now); err != ErrCircuitOpen {
+		t.Fatalf("half-open, probes exhausted: err = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	first(outcomeSuccess)
+	second(outcomeSuccess)
+
+	if got, want := cb.state, CircuitClosed; got != want {
+		t.Fatalf("state after probes = %s, want %s", got, want)
+	}
+
+	if got, want := strings.Join(transitions, ","), "closed->open,open->half-open,half-open->closed"; got != want {
+		t.Errorf("transitions = %q, want %q", got, want)
+	}
+}
+
+func TestSearchCircuitBreaker(t *testing.T) {
+	requests := 0
+
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		requests++
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("all is lost!")),
+			Header:     make(http.Header),
+			StatusCode: http.StatusInternalServerError,
+		}
+		resp.Header.Add("Content-Type", "text/plain")
+		return resp, nil
+	}
+
+	c := NewClient(
+		SetBaseURL("/"),
+		SetHTTPClient(&http.Client{Transport: mockT}),
+		SetCircuitBreaker(CircuitBreakerSettings{
+			FailureRatio: 1,
+			MinRequests:  2,
+			OpenTimeout:  time.Hour,
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Search(context.Background(), nil); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("[%d] err = %v, want HTTP error", i, err)
+		}
+	}
+
+	if got, want := c.CircuitState(), CircuitOpen; got != want {
+		t.Fatalf("c.CircuitState() = %s, want %s", got, want)
+	}
+
+	if _, err := c.Search(context.Background(), nil); err != ErrCircuitOpen {
+		t.Errorf("err = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	if got, want := requests, 2; got != want {
+		t.Errorf("requests = %d, want %d", got, want)
+	}
+}
+
+func TestCircuitBreaker_IgnoredProbe(t *testing.T) {
+	cb := &circuitBreaker{
+		settings: CircuitBreakerSettings{
+			FailureRatio:   0.5,
+			MinRequests:    1,
+			OpenTimeout:    time.Minute,
+			HalfOpenProbes: 1,
+		},
+	}
+
+	done, err := cb.allow(time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done(outcomeFailure)
+
+	halfOpen := time.Now().Add(2 * time.Minute)
+
+	probe, err := cb.allow(halfOpen)
+	if err != nil {
+		t.Fatalf("half-open: unexpected error: %v", err)
+	}
+	probe(outcomeIgnored)
+
+	if got, want := cb.state, CircuitHalfOpen; got != want {
+		t.Fatalf("state after ignored probe = %s, want %s", got, want)
+	}
+
+	probe, err = cb.allow(halfOpen)
+	if err != nil {
+		t.Fatalf("half-open, probe slot freed: unexpected error: %v", err)
+	}
+	probe(outcomeFailure)
+
+	if got, want := cb.state, CircuitOpen; got != want {
+		t.Errorf("state after failed probe = %s, want %s", got, want)
+	}
+}
+
+func TestSearchCircuitBreaker_CanceledProbe(t *testing.T) {
+	started := make(chan struct{})
+
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		close(started)
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	}
+
+	c := NewClient(
+		SetBaseURL("/"),
+		SetHTTPClient(&http.Client{Transport: mockT}),
+		SetCircuitBreaker(CircuitBreakerSettings{OpenTimeout: time.Hour}),
+	)
+
+	c.breaker.mu.Lock()
+	c.breaker.state = CircuitOpen
+	c.breaker.openedAt = time.Now().Add(-2 * time.Hour)
+	c.breaker.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	if _, err := c.Search(ctx, nil, NoCoalescing()); err == nil {
+		t.Fatal("Search: got nil, want err")
+	}
+
+	if got, want := c.CircuitState(), CircuitHalfOpen; got != want {
+		t.Errorf("c.CircuitState() = %s, want %s", got, want)
+	}
+}
+
+func TestRequestOutcome(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for n, tc := range []struct {
+		ctx  context.Context
+		resp *http.Response
+		err  error
+		want outcome
+	}{
+		{context.Background(), &http.Response{StatusCode: 200}, nil, outcomeSuccess},
+		{context.Background(), &http.Response{StatusCode: 404}, nil, outcomeSuccess},
+		{context.Background(), &http.Response{StatusCode: 503}, nil, outcomeFailure},
+		{context.Background(), nil, errors.New("connection reset"), outcomeFailure},
+		{canceled, nil, context.Canceled, outcomeIgnored},
+	} {
+		if got := requestOutcome(tc.ctx, tc.resp, tc.err); got != tc.want {
+			t.Errorf("[%d] requestOutcome = %d, want %d", n, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package cmoresearch
+
+import (
+	"container/list"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStatus tells whether a response was served from the cache.
+type CacheStatus string
+
+// Cache statuses reported in Meta.Cache when a cache is configured.
+const (
+	CacheHit         CacheStatus = "hit"
+	CacheMiss        CacheStatus = "miss"
+	CacheRevalidated CacheStatus = "revalidated"
+)
+
+// Cache stores search responses. Implementations must be safe for concurrent
+// use.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+}
+
+// CacheEntry is a response stored in a Cache.
+type CacheEntry struct {
+	Response Response
+	Expires  time.Time
+}
+
+// SetCache is an option to set a response cache when creating a new client.
+// Successful responses are cached for ttl, or for the max-age given in the
+// Cache-Control header of the response. Responses with Cache-Control no-store
+// are never cached.
+//
+// Expired responses carrying an ETag or Last-Modified header are revalidated
+// with a conditional request, and reused if the search service responds with
+// 304 Not Modified.
+func SetCache(cache Cache, ttl time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+func (c *Client) cachedSearch(req *http.Request) (Response, error) {
+	key := cacheKey(req.URL)
+
+	e, cached := c.cache.Get(key)
+	if cached && time.Now().Before(e.Expires) {
+		res := e.Response.copy()
+		res.Meta.Attempts = 0
+		res.Meta.Wait = 0
+		res.Meta.Cache = CacheHit
+		return res, nil
+	}
+
+	if cached {
+		req = conditionalRequest(req, e.Response.Meta.Header)
+	}
+
+	res, err := c.coalescedFetch(req)
+
+	if err == errNotModified && cached {
+		header := e.Response.Meta.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		for k, v := range res.Meta.Header {
+			header[k] = v
+		}
+		e.Response.Meta.Header = header
+
+		if ttl, store := cacheTTL(res.Meta.Header, c.cacheTTL); store {
+			e.Expires = time.Now().Add(ttl)
+			c.cache.Set(key, CacheEntry{Response: e.Response.copy(), Expires: e.Expires})
+		}
+
+		revalidated := e.Response.copy()
+		revalidated.Meta.Attempts = res.Meta.Attempts
+		revalidated.Meta.Wait = res.Meta.Wait
+		revalidated.Meta.Cache = CacheRevalidated
+		return revalidated, nil
+	}
+
+	res.Meta.Cache = CacheMiss
+	if err != nil {
+		return res, err
+	}
+
+	ttl, store := cacheTTL(res.Meta.Header, c.cacheTTL)
+	if store && (ttl > 0 || hasValidators(res.Meta.Header)) {
+		c.cache.Set(key, CacheEntry{
+			Response: res.copy(),
+			Expires:  time.Now().Add(ttl),
+		})
+	}
+
+	return res, nil
+}
+
+// conditionalRequest returns a copy of req revalidating a response with the
+// validators in h, or req itself if h holds no validators.
+func conditionalRequest(req *http.Request, h http.Header) *http.Request {
+	if !hasValidators(h) {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+
+	if etag := h.Get("ETag"); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified := h.Get("Last-Modified"); lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return req
+}
+
+func hasValidators(h http.Header) bool {
+	return h.Get("ETag") != "" || h.Get("Last-Modified") != ""
+}
+
+// cacheKey returns a canonical representation of u, where the order of
+// parameters and of the comma separated fields list does not matter. Values
+// are otherwise kept as is, since the search service may tell them apart.
+func cacheKey(u *url.URL) string {
+	query := u.Query()
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(u.Path)
+	b.WriteByte('?')
+
+	for i, k := range keys {
+		values := make([]string, len(query[k]))
+		for j, v := range query[k] {
+			if k == "fields" {
+				fields := strings.Split(v, ",")
+				sort.Strings(fields)
+				v = strings.Join(fields, ",")
+			}
+			values[j] = v
+		}
+
+		for j, v := range values {
+			if i > 0 || j > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(v))
+		}
+	}
+
+	return b.String()
+}
+
+// cacheTTL returns how long a response with header h is fresh, and whether it
+// may be stored at all.
+func cacheTTL(h http.Header, defaultTTL time.Duration) (time.Duration, bool) {
+	ttl := defaultTTL
+
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case directive == "no-store":
+			return 0, false
+		case directive == "no-cache":
+			ttl = 0
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return ttl, true
+}
+
+// LRUCache is a size-bounded Cache evicting the least recently used entry
+// when full.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUCache returns a new LRUCache holding at most size entries. A cache of
+// size zero or less holds no entries.
+func NewLRUCache(size int) *LRUCache {
+	if size < 0 {
+		size = 0
+	}
+
+	return &LRUCache{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry stored for key, if any.
+func (lc *LRUCache) Get(key string) (CacheEntry, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	el, ok := lc.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	lc.ll.MoveToFront(el)
+
+	return el.Value.(*lruItem).entry, true
+}
+
+// Set stores entry for key, evicting the least recently used entry if the
+// cache is full.
+func (lc *LRUCache) Set(key string, entry CacheEntry) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if el, ok := lc.entries[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		lc.ll.MoveToFront(el)
+		return
+	}
+
+	lc.entries[key] = lc.ll.PushFront(&lruItem{key: key, entry: entry})
+
+	for lc.ll.Len() > lc.size {
+		el := lc.ll.Back()
+		lc.ll.Remove(el)
+		delete(lc.entries, el.Value.(*lruItem).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (lc *LRUCache) Len() int {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	return lc.ll.Len()
+}
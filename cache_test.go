@@ -0,0 +1,310 @@
+package cmoresearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchCache(t *testing.T) {
+	newClient := func(requests *int, cacheControl string, options ...func(*Client)) *Client {
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			*requests++
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":1,"assets":[{"type":"movie","video_id":"1","title_sv":"Solsidan"}]}`)),
+				Header:     make(http.Header),
+				StatusCode: http.StatusOK,
+			}
+			resp.Header.Add("Content-Type", "application/json")
+			if cacheControl != "" {
+				resp.Header.Add("Cache-Control", cacheControl)
+			}
+			return resp, nil
+		}
+
+		options = append([]func(*Client){
+			SetBaseURL("/"),
+			SetHTTPClient(&http.Client{Transport: mockT}),
+		}, options...)
+
+		return NewClient(options...)
+	}
+
+	t.Run("HitAndMiss", func(t *testing.T) {
+		requests := 0
+
+		c := newClient(&requests, "", SetCache(NewLRUCache(10), time.Minute))
+
+		first, err := c.Search(context.Background(), url.Values{"site": {"cmore.se"}, "lang": {"sv"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := first.Meta.Cache, CacheMiss; got != want {
+			t.Errorf("first.Meta.Cache = %q, want %q", got, want)
+		}
+
+		first.Hits[0].(*Asset).TitleSv = "modified"
+
+		second, err := c.Search(context.Background(), url.Values{"lang": {"sv"}, "site": {"cmore.se"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := second.Meta.Cache, CacheHit; got != want {
+			t.Errorf("second.Meta.Cache = %q, want %q", got, want)
+		}
+
+		if got, want := second.Hits[0].(*Asset).TitleSv, "Solsidan"; got != want {
+			t.Errorf("second.Hits[0].TitleSv = %q, want %q", got, want)
+		}
+
+		if got, want := requests, 1; got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("AppName", func(t *testing.T) {
+		requests := 0
+
+		cache := NewLRUCache(10)
+
+		c1 := newClient(&requests, "", SetCache(cache, time.Minute), SetAppName("app1"))
+		c2 := newClient(&requests, "", SetCache(cache, time.Minute), SetAppName("app2"))
+
+		for _, c := range []*Client{c1, c2} {
+			if _, err := c.Search(context.Background(), url.Values{"site": {"cmore.se"}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if got, want := requests, 2; got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		requests := 0
+
+		c := newClient(&requests, "max-age=0", SetCache(NewLRUCache(10), time.Minute))
+
+		for i := 0; i < 2; i++ {
+			res, err := c.Search(context.Background(), url.Values{"site": {"cmore.se"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := res.Meta.Cache, CacheMiss; got != want {
+				t.Errorf("[%d] res.Meta.Cache = %q, want %q", i, got, want)
+			}
+		}
+
+		if got, want := requests, 2; got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		requests := 0
+
+		c := newClient(&requests, "")
+
+		res, err := c.Search(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := res.Meta.Cache, CacheStatus(""); got != want {
+			t.Errorf("res.Meta.Cache = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestSearchCacheRevalidation(t *testing.T) {
+	for _, tc := range []struct {
+		description    string
+		validator      string
+		conditional    string
+		validatorValue string
+	}{
+		{"ETag", "ETag", "If-None-Match", `"v1"`},
+		{"LastModified", "Last-Modified", "If-Modified-Since", "Fri, 01 Mar 2019 12:00:00 GMT"},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			var conditionals []string
+
+			var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+				conditionals = append(conditionals, r.Header.Get(tc.conditional))
+
+				if r.Header.Get(tc.conditional) == tc.validatorValue {
+					return &http.Response{
+						Body:       ioutil.NopCloser(strings.NewReader("")),
+						Header:     http.Header{"Cache-Control": {"max-age=0"}, "X-Revalidated": {"yes"}},
+						StatusCode: http.StatusNotModified,
+					}, nil
+				}
+
+				resp := &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":1,"assets":[{"type":"movie","video_id":"1"}]}`)),
+					Header:     make(http.Header),
+					StatusCode: http.StatusOK,
+				}
+				resp.Header.Add("Content-Type", "application/json")
+				resp.Header.Add("Cache-Control", "max-age=0")
+				resp.Header.Add(tc.validator, tc.validatorValue)
+				return resp, nil
+			}
+
+			c := NewClient(
+				SetBaseURL("/"),
+				SetHTTPClient(&http.Client{Transport: mockT}),
+				SetCache(NewLRUCache(10), time.Minute),
+			)
+
+			for i, want := range []CacheStatus{CacheMiss, CacheRevalidated, CacheRevalidated} {
+				res, err := c.Search(context.Background(), nil)
+				if err != nil {
+					t.Fatalf("[%d] unexpected error: %v", i, err)
+				}
+
+				if got := res.Meta.Cache; got != want {
+					t.Errorf("[%d] res.Meta.Cache = %q, want %q", i, got, want)
+				}
+
+				if got, want := res.Meta.StatusCode, http.StatusOK; got != want {
+					t.Errorf("[%d] res.Meta.StatusCode = %d, want %d", i, got, want)
+				}
+
+				if got, want := len(res.Hits), 1; got != want {
+					t.Fatalf("[%d] len(res.Hits) = %d, want %d", i, got, want)
+				}
+			}
+
+			if got, want := strings.Join(conditionals, "|"), "|"+tc.validatorValue+"|"+tc.validatorValue; got != want {
+				t.Errorf("%s headers = %q, want %q", tc.conditional, got, want)
+			}
+		})
+	}
+
+	t.Run("NotModifiedWithoutCache", func(t *testing.T) {
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				Header:     make(http.Header),
+				StatusCode: http.StatusNotModified,
+			}, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		_, err := c.Search(context.Background(), nil)
+		if err == nil {
+			t.Fatal("got nil, want error")
+		}
+
+		if got, want := err.Error(), "304 Not Modified"; got != want {
+			t.Errorf("err.Error() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	for n, tc := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{"/search?a=1&b=2", "/search?b=2&a=1", true},
+		{"/search?fields=title_sv,type", "/search?fields=type,title_sv", true},
+		{"/search?a=1&a=2", "/search?a=2&a=1", false},
+		{"/search?fields=title_sv,type", "/search?fields=type,%20title_sv", false},
+		{"/search?site=cmore.se", "/search?site=cmore.se%20", false},
+		{"/search?a=1", "/search?a=2", false},
+		{"/search?video_ids=1,2", "/search?video_ids=2,1", false},
+		{"/search?client=app1", "/search?client=app2", false},
+		{"/search?a=1", "/other?a=1", false},
+	} {
+		a, _ := url.Parse(tc.a)
+		b, _ := url.Parse(tc.b)
+
+		if got, want := cacheKey(a) == cacheKey(b), tc.equal; got != want {
+			t.Errorf("[%d] %q == %q is %t, want %t", n, cacheKey(a), cacheKey(b), got, want)
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	for n, tc := range []struct {
+		cacheControl string
+		want         time.Duration
+		store        bool
+	}{
+		{"", time.Minute, true},
+		{"max-age=30", 30 * time.Second, true},
+		{"public, Max-Age=10", 10 * time.Second, true},
+		{"max-age=0", 0, true},
+		{"no-cache", 0, true},
+		{"no-store", 0, false},
+		{"max-age=garbage", time.Minute, true},
+	} {
+		h := http.Header{"Cache-Control": {tc.cacheControl}}
+
+		if got, store := cacheTTL(h, time.Minute); got != tc.want || store != tc.store {
+			t.Errorf("[%d] cacheTTL = %s, %t, want %s, %t", n, got, store, tc.want, tc.store)
+		}
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	lc := NewLRUCache(2)
+
+	lc.Set("a", CacheEntry{Response: Response{TotalHits: 1}})
+	lc.Set("b", CacheEntry{Response: Response{TotalHits: 2}})
+
+	if _, ok := lc.Get("a"); !ok {
+		t.Fatal(`lc.Get("a") not found`)
+	}
+
+	lc.Set("c", CacheEntry{Response: Response{TotalHits: 3}})
+
+	if _, ok := lc.Get("b"); ok {
+		t.Error(`lc.Get("b") found, want evicted`)
+	}
+
+	if e, ok := lc.Get("a"); !ok || e.Response.TotalHits != 1 {
+		t.Errorf(`lc.Get("a") = %v, %t`, e.Response.TotalHits, ok)
+	}
+
+	if e, ok := lc.Get("c"); !ok || e.Response.TotalHits != 3 {
+		t.Errorf(`lc.Get("c") = %v, %t`, e.Response.TotalHits, ok)
+	}
+
+	lc.Set("c", CacheEntry{Response: Response{TotalHits: 4}})
+
+	if e, _ := lc.Get("c"); e.Response.TotalHits != 4 {
+		t.Errorf(`lc.Get("c").Response.TotalHits = %d, want 4`, e.Response.TotalHits)
+	}
+
+	if got, want := lc.Len(), 2; got != want {
+		t.Errorf("lc.Len() = %d, want %d", got, want)
+	}
+}
+
+func TestLRUCache_NonPositiveSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		lc := NewLRUCache(size)
+
+		lc.Set("a", CacheEntry{Response: Response{TotalHits: 1}})
+
+		if _, ok := lc.Get("a"); ok {
+			t.Errorf(`[%d] lc.Get("a") found, want evicted`, size)
+		}
+
+		if got, want := lc.Len(), 0; got != want {
+			t.Errorf("[%d] lc.Len() = %d, want %d", size, got, want)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package cmoresearch
 
 import (
+	"io"
 	"mime"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
 var (
@@ -15,15 +18,32 @@ var (
 
 // Client is a client for the search service.
 type Client struct {
-	appName    string
-	baseURL    *url.URL
-	httpClient *http.Client
-	debugLogf  func(string, ...interface{})
+	appName     string
+	baseURL     *url.URL
+	httpClient  *http.Client
+	debugLogf   func(string, ...interface{})
+	retryPolicy RetryPolicy
+	cache       Cache
+	cacheTTL    time.Duration
+
+	flightsMu sync.Mutex
+	flights   map[string]*flight
+
+	rateLimiter *rateLimiter
+	conns       chan struct{}
+	breaker     *circuitBreaker
+
+	strictDecoding *StrictDecoding
+
+	recorder io.Writer
 }
 
 // NewClient returns a new search client.
 func NewClient(options ...func(*Client)) *Client {
-	c := &Client{baseURL: defaultBaseURL}
+	c := &Client{
+		baseURL: defaultBaseURL,
+		flights: make(map[string]*flight),
+	}
 
 	for _, o := range options {
 		o(c)
@@ -34,6 +54,13 @@ func NewClient(options ...func(*Client)) *Client {
 		c.httpClient = &dup
 	}
 
+	if c.recorder != nil {
+		// Wrap the transport of a copy, leaving the given client as is.
+		dup := *c.httpClient
+		c.httpClient = &dup
+		c.httpClient.Transport = NewRecorder(c.recorder, c.httpClient.Transport)
+	}
+
 	if c.debugLogf != nil {
 		if c.httpClient.Transport == nil {
 			c.httpClient.Transport = http.DefaultTransport
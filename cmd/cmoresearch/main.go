@@ -0,0 +1,213 @@
+// Command cmoresearch performs a search in C More's search service and prints
+// the hits.
+//
+// Usage:
+//
+//	cmoresearch [flags]
+//
+// Query parameters are given as flags, e.g.
+//
+//	cmoresearch --site cmore.se --brand-id 34515 --season 1 --sort-by episode_number
+//
+// Parameters without a flag of their own can be given with --param, which may
+// be repeated:
+//
+//	cmoresearch --param genres=Drama --param lang=sv
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cmoresearch "github.com/TV4/cmoresearch-go"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// params is a flag.Value collecting key=value query parameters.
+type params url.Values
+
+func (p params) String() string {
+	return url.Values(p).Encode()
+}
+
+func (p params) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("%q is not key=value", s)
+	}
+	url.Values(p).Add(kv[0], kv[1])
+	return nil
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cmoresearch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		baseURL   = fs.String("base-url", "", "base URL of the search service")
+		appName   = fs.String("app-name", "cmoresearch-cli", "value of the client parameter")
+		requestID = fs.String("request-id", "", "value of the X-Request-Id header")
+		output    = fs.String("output", "table", "output format: table, json or ndjson")
+		timeout   = fs.Duration("timeout", 10*time.Second, "timeout of the search")
+	)
+
+	query := url.Values{}
+
+	for _, p := range []struct{ flag, param, usage string }{
+		{"site", "site", "site, e.g. cmore.se"},
+		{"lang", "lang", "language, e.g. sv"},
+		{"device-type", "device_type", "device type, e.g. tve_web"},
+		{"video-ids", "video_ids", "comma separated video IDs"},
+		{"brand-id", "brand_id", "brand ID"},
+		{"season", "season", "season number"},
+		{"sort-by", "sort_by", "field to sort by"},
+		{"order", "order", "sort order: asc or desc"},
+		{"page", "page", "page number, starting at 1"},
+		{"page-size", "page_size", "number of hits per page"},
+		{"fields", "fields", "comma separated fields to return"},
+	} {
+		param := p.param
+		fs.Func(p.flag, p.usage, func(s string) error {
+			query.Set(param, s)
+			return nil
+		})
+	}
+
+	fs.Var(params(query), "param", "additional query parameter as key=value, may be repeated")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	switch *output {
+	case "table", "json", "ndjson":
+	default:
+		fmt.Fprintf(stderr, "unknown output format %q\n", *output)
+		return 2
+	}
+
+	options := []func(*cmoresearch.Client){cmoresearch.SetAppName(*appName)}
+	if *baseURL != "" {
+		options = append(options, cmoresearch.SetBaseURL(*baseURL))
+	}
+
+	client := cmoresearch.NewClient(options...)
+
+	var requestOptions []func(*http.Request)
+	if *requestID != "" {
+		requestOptions = append(requestOptions, cmoresearch.SetRequestID(*requestID))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	res, err := client.Search(ctx, query, requestOptions...)
+	if err != nil {
+		var ae *cmoresearch.APIError
+		if errors.As(err, &ae) {
+			fmt.Fprintln(stderr, ae.Message)
+		} else {
+			fmt.Fprintln(stderr, err)
+		}
+		return 1
+	}
+
+	if err := printResponse(stdout, res, *output); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func printResponse(w io.Writer, res cmoresearch.Response, output string) error {
+	switch output {
+	case "json":
+		hits := make([]json.RawMessage, 0, len(res.Hits))
+		for _, h := range res.Hits {
+			data, err := hitJSON(h)
+			if err != nil {
+				return err
+			}
+			hits = append(hits, data)
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			TotalHits int               `json:"total_hits"`
+			Hits      []json.RawMessage `json:"assets"`
+		}{res.TotalHits, hits})
+	case "ndjson":
+		for _, h := range res.Hits {
+			data, err := hitJSON(h)
+			if err != nil {
+				return err
+			}
+
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, data); err != nil {
+				return err
+			}
+			buf.WriteByte('\n')
+
+			if _, err := buf.WriteTo(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "TYPE\tID\tTITLE\tEPISODE")
+
+	for _, h := range res.Hits {
+		s := h.Subset()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Type, s.ID, s.Title(cmoresearch.LanguageSv), episode(h))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%d of %d hits\n", len(res.Hits), res.TotalHits)
+	return err
+}
+
+// episode returns the season and episode of h, e.g. S01E02, if known.
+func episode(h cmoresearch.Hit) string {
+	a, ok := h.(*cmoresearch.Asset)
+	if !ok || a.Season.Number == 0 {
+		return ""
+	}
+
+	if a.EpisodeNumber == 0 {
+		return fmt.Sprintf("S%02d", a.Season.Number)
+	}
+
+	return fmt.Sprintf("S%02dE%02d", a.Season.Number, a.EpisodeNumber)
+}
+
+// hitJSON returns the JSON of h as received from the search service, if
+// available.
+func hitJSON(h cmoresearch.Hit) (json.RawMessage, error) {
+	if r, ok := h.(interface{ Raw() json.RawMessage }); ok && len(r.Raw()) > 0 {
+		return r.Raw(), nil
+	}
+	return json.Marshal(h)
+}
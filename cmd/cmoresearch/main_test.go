@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	cmoresearch "github.com/TV4/cmoresearch-go"
+	"github.com/TV4/cmoresearch-go/cmoresearchtest"
+)
+
+func TestRun(t *testing.T) {
+	srv := cmoresearchtest.NewServer(
+		&cmoresearch.Asset{
+			Type:          "episode",
+			VideoID:       "2222333",
+			TitleSv:       "Avsnitt 2",
+			Brand:         cmoresearch.Brand{ID: "34515"},
+			Season:        cmoresearch.Season{Number: 1},
+			EpisodeNumber: 2,
+		},
+		&cmoresearch.Series{Type: "series", BrandID: "34515", TitleSv: "Solsidan"},
+	)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		description string
+		args        []string
+		code        int
+		stdout      []string
+		stderr      string
+	}{
+		{
+			"Table",
+			[]string{"--brand-id", "34515"},
+			0,
+			[]string{"TYPE", "episode  2222333  Avsnitt 2  S01E02", "series   34515    Solsidan", "2 of 2 hits"},
+			"",
+		},
+		{
+			"JSON",
+			[]string{"--video-ids", "2222333", "--output", "json"},
+			0,
+			[]string{`"total_hits": 1`, `"video_id": "2222333"`},
+			"",
+		},
+		{
+			"NDJSON",
+			[]string{"--param", "brand_id=34515", "--output", "ndjson", "--fields", "type"},
+			0,
+			[]string{"{\"type\":\"episode\"}\n{\"type\":\"series\"}\n"},
+			"",
+		},
+		{"APIError", []string{"--site", "example.com"}, 1, nil, "Invalid parameters: site\n"},
+		{"UnknownOutput", []string{"--output", "xml"}, 2, nil, "unknown output format \"xml\"\n"},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(append([]string{"--base-url", srv.URL, "--request-id", "abc"}, tc.args...), &stdout, &stderr)
+
+			if code != tc.code {
+				t.Errorf("code = %d, want %d", code, tc.code)
+			}
+
+			for _, s := range tc.stdout {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("stdout %q does not contain %q", stdout.String(), s)
+				}
+			}
+
+			if got, want := stderr.String(), tc.stderr; got != want {
+				t.Errorf("stderr = %q, want %q", got, want)
+			}
+		})
+	}
+
+	t.Run("ServerError", func(t *testing.T) {
+		srv.InjectFault(cmoresearchtest.FaultRule{Times: 1, Fault: cmoresearchtest.StatusError(http.StatusBadGateway)})
+
+		var stdout, stderr bytes.Buffer
+
+		if got, want := run([]string{"--base-url", srv.URL}, &stdout, &stderr), 1; got != want {
+			t.Errorf("code = %d, want %d", got, want)
+		}
+
+		if got, want := stderr.String(), "502 Bad Gateway\n"; got != want {
+			t.Errorf("stderr = %q, want %q", got, want)
+		}
+	})
+}
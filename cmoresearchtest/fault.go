@@ -0,0 +1,192 @@
+package cmoresearchtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+// Fault makes the server misbehave when serving a request. It is given the
+// handler serving the request normally, which it may call.
+type Fault func(w http.ResponseWriter, r *http.Request, next http.Handler)
+
+// FaultRule decides when a fault is injected.
+type FaultRule struct {
+	// Path, if set, limits the rule to requests with a path starting with
+	// Path, e.g. "/flaky" for a client with the base URL of the server
+	// followed by /flaky.
+	Path string
+
+	// Probability, if non-zero, is the probability of the fault being
+	// injected into a matching request. If zero, it is always injected.
+	Probability float64
+
+	// Times, if non-zero, is the number of times the fault is injected
+	// before the rule is removed.
+	Times int
+
+	Fault Fault
+}
+
+// InjectFault adds a fault rule to the server. For each request the first
+// matching rule, in the order added, injects its fault.
+func (s *Server) InjectFault(rule FaultRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &rule)
+}
+
+// ClearFaults removes all fault rules from the server.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// fault returns the fault to inject into r, or nil if none.
+func (s *Server) fault(r *http.Request) Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, rule := range s.faults {
+		if !strings.HasPrefix(r.URL.Path, rule.Path) {
+			continue
+		}
+
+		if rule.Probability > 0 && rand.Float64() >= rule.Probability {
+			continue
+		}
+
+		if rule.Times > 0 {
+			if rule.Times--; rule.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+
+		return rule.Fault
+	}
+
+	return nil
+}
+
+// Latency delays the response by d, or until the request is canceled.
+func Latency(d time.Duration) Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+			next.ServeHTTP(w, r)
+		case <-r.Context().Done():
+		}
+	}
+}
+
+// StatusError responds with the given status code and a plain text body.
+func StatusError(code int) Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		http.Error(w, http.StatusText(code), code)
+	}
+}
+
+// APIError responds with the given status code and the JSON of an API error
+// with message.
+func APIError(code int, message string) Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		writeError(w, code, message)
+	}
+}
+
+// ContentType serves the response with the given Content-Type.
+func ContentType(contentType string) Fault {
+	return rewrite(func(h http.Header, body []byte) []byte {
+		h.Set("Content-Type", contentType)
+		return body
+	})
+}
+
+// TruncatedJSON cuts the response body in half.
+func TruncatedJSON() Fault {
+	return rewrite(func(h http.Header, body []byte) []byte {
+		return body[:len(body)/2]
+	})
+}
+
+// MalformedJSON inserts a stray comma at the start of the response body.
+func MalformedJSON() Fault {
+	return rewrite(func(h http.Header, body []byte) []byte {
+		return append([]byte("{,"), bytes.TrimPrefix(body, []byte("{"))...)
+	})
+}
+
+// MissingType removes the type field from every hit in the response.
+func MissingType() Fault {
+	return rewrite(func(h http.Header, body []byte) []byte {
+		var v struct {
+			TotalHits int                          `json:"total_hits"`
+			Hits      []map[string]json.RawMessage `json:"assets"`
+		}
+
+		if err := json.Unmarshal(body, &v); err != nil {
+			return body
+		}
+
+		for _, hit := range v.Hits {
+			delete(hit, "type")
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+
+		return data
+	})
+}
+
+// ConnectionReset resets the connection without responding.
+func ConnectionReset() Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			panic(http.ErrAbortHandler)
+		}
+
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+
+		if tc, ok := conn.(*net.TCPConn); ok {
+			tc.SetLinger(0)
+		}
+
+		conn.Close()
+	}
+}
+
+// rewrite returns a fault serving the request normally, then passing the
+// response header and body through fn before writing them.
+func rewrite(fn func(h http.Header, body []byte) []byte) Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+
+		body := fn(w.Header(), rec.Body.Bytes())
+
+		w.WriteHeader(rec.Code)
+		w.Write(body)
+	}
+}
@@ -0,0 +1,120 @@
+package cmoresearchtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	cmoresearch "github.com/TV4/cmoresearch-go"
+)
+
+func TestServer_InjectFault(t *testing.T) {
+	newServer := func(rule FaultRule) *Server {
+		srv := NewServer(&cmoresearch.Asset{Type: "movie", VideoID: "1"})
+		srv.InjectFault(rule)
+		return srv
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			description string
+			fault       Fault
+			check       func(error) bool
+		}{
+			{"StatusError", StatusError(http.StatusBadGateway), func(err error) bool {
+				return err.Error() == "502 Bad Gateway"
+			}},
+			{"APIError", APIError(http.StatusServiceUnavailable, "Down for maintenance"), func(err error) bool {
+				var ae *cmoresearch.APIError
+				return errors.As(err, &ae) && ae.Code == 503 && ae.Message == "Down for maintenance"
+			}},
+			{"ContentType", ContentType("text/html"), func(err error) bool {
+				return err == cmoresearch.ErrContentTypeNotJSON
+			}},
+			{"TruncatedJSON", TruncatedJSON(), func(err error) bool {
+				return err != nil && strings.Contains(err.Error(), "unexpected EOF")
+			}},
+			{"MalformedJSON", MalformedJSON(), func(err error) bool {
+				return err != nil && strings.Contains(err.Error(), "invalid character")
+			}},
+			{"MissingType", MissingType(), func(err error) bool {
+				return err == cmoresearch.ErrTypeMissing
+			}},
+			{"ConnectionReset", ConnectionReset(), func(err error) bool {
+				var ue *url.Error
+				return errors.As(err, &ue)
+			}},
+		} {
+			t.Run(tc.description, func(t *testing.T) {
+				srv := newServer(FaultRule{Fault: tc.fault})
+				defer srv.Close()
+
+				_, err := srv.Client().Search(context.Background(), url.Values{})
+
+				if !tc.check(err) {
+					t.Errorf("unexpected error %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		srv := newServer(FaultRule{Fault: Latency(time.Second)})
+		defer srv.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		if _, err := srv.Client().Search(ctx, url.Values{}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("PathAndTimes", func(t *testing.T) {
+		srv := newServer(FaultRule{Path: "/flaky", Times: 2, Fault: StatusError(http.StatusInternalServerError)})
+		defer srv.Close()
+
+		if _, err := srv.Client().Search(context.Background(), url.Values{}); err != nil {
+			t.Fatalf("Search: unexpected error %v", err)
+		}
+
+		flaky := cmoresearch.NewClient(cmoresearch.SetBaseURL(srv.URL + "/flaky"))
+
+		for i := 0; i < 2; i++ {
+			if _, err := flaky.Search(context.Background(), url.Values{}); err == nil {
+				t.Fatalf("[%d] Search: got nil, want err", i)
+			}
+		}
+
+		res, err := flaky.Search(context.Background(), url.Values{})
+		if err != nil {
+			t.Fatalf("Search: unexpected error %v", err)
+		}
+
+		if got, want := res.TotalHits, 1; got != want {
+			t.Errorf("res.TotalHits = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Probability", func(t *testing.T) {
+		srv := newServer(FaultRule{Probability: 0.5, Fault: StatusError(http.StatusInternalServerError)})
+		defer srv.Close()
+
+		client := srv.Client()
+
+		var failed int
+		for i := 0; i < 200; i++ {
+			if _, err := client.Search(context.Background(), url.Values{}, cmoresearch.NoCoalescing()); err != nil {
+				failed++
+			}
+		}
+
+		if failed < 50 || failed > 150 {
+			t.Errorf("failed = %d, want about 100", failed)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+/*
+Package cmoresearchtest provides a search service for use in tests of code
+using the cmoresearch package.
+
+Usage
+
+A small usage example:
+
+		srv := cmoresearchtest.NewServer(
+			&cmoresearch.Asset{Type: "episode", VideoID: "2222333", Brand: cmoresearch.Brand{ID: "34515"}},
+			&cmoresearch.Series{Type: "series", BrandID: "34515"},
+		)
+		defer srv.Close()
+
+		client := srv.Client()
+
+		res, err := client.Search(ctx, url.Values{"video_ids": {"2222333"}})
+*/
+package cmoresearchtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	cmoresearch "github.com/TV4/cmoresearch-go"
+)
+
+const defaultPageSize = 100
+
+// Server is a search service serving /search, under any base path, from an
+// in-memory catalog of hits. It supports the site, video_ids, brand_id,
+// season, sort_by, order, page, page_size and fields parameters, and responds
+// with the JSON of an *cmoresearch.APIError to invalid parameters.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.RWMutex
+	hits   []cmoresearch.Hit
+	faults []*FaultRule
+}
+
+// NewServer starts and returns a new Server with hits in its catalog. The
+// caller should call Close when finished, to shut it down.
+func NewServer(hits ...cmoresearch.Hit) *Server {
+	s := &Server{}
+	s.Add(hits...)
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Add adds hits to the catalog of the server. The hits must not be changed
+// afterwards.
+func (s *Server) Add(hits ...cmoresearch.Hit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, h := range hits {
+		// Subset is computed lazily, so compute it here rather than in
+		// concurrent searches.
+		h.Subset()
+	}
+
+	s.hits = append(s.hits, hits...)
+}
+
+// Client returns a new search client using the server, with options applied
+// after setting its base URL.
+func (s *Server) Client(options ...func(*cmoresearch.Client)) *cmoresearch.Client {
+	return cmoresearch.NewClient(append([]func(*cmoresearch.Client){cmoresearch.SetBaseURL(s.URL)}, options...)...)
+}
+
+// ServeHTTP serves a search request from the catalog of the server, unless a
+// fault is injected.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if fault := s.fault(r); fault != nil {
+		fault(w, r, http.HandlerFunc(s.serveSearch))
+		return
+	}
+
+	s.serveSearch(w, r)
+}
+
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request) {
+	if path.Base(r.URL.Path) != "search" {
+		writeError(w, http.StatusNotFound, "Not found: "+r.URL.Path)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed: "+r.Method)
+		return
+	}
+
+	query := r.URL.Query()
+
+	if invalid := invalidParams(query); len(invalid) > 0 {
+		writeError(w, http.StatusBadRequest, "Invalid parameters: "+strings.Join(invalid, ", "))
+		return
+	}
+
+	s.mu.RLock()
+	docs := make([]map[string]json.RawMessage, 0, len(s.hits))
+	for _, h := range s.hits {
+		if !matches(h, query) {
+			continue
+		}
+
+		doc, err := document(h)
+		if err != nil {
+			s.mu.RUnlock()
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		docs = append(docs, doc)
+	}
+	s.mu.RUnlock()
+
+	if sortBy := query.Get("sort_by"); sortBy != "" {
+		sortDocs(docs, sortBy, query.Get("order") == string(cmoresearch.OrderDesc))
+	}
+
+	total := len(docs)
+
+	page, pageSize := intParam(query, "page", 1), intParam(query, "page_size", defaultPageSize)
+	docs = paginate(docs, page, pageSize)
+
+	if fields := query.Get("fields"); fields != "" {
+		for i, doc := range docs {
+			docs[i] = selectFields(doc, strings.Split(fields, ","))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	json.NewEncoder(w).Encode(struct {
+		TotalHits int                          `json:"total_hits"`
+		Hits      []map[string]json.RawMessage `json:"assets"`
+	}{total, docs})
+}
+
+// invalidParams returns the names of the parameters in query with values not
+// accepted by the search service.
+func invalidParams(query url.Values) []string {
+	var invalid []string
+
+	for key, values := range query {
+		if err := cmoresearch.NewQuery().Set(key, values[0]).Validate(); err != nil {
+			invalid = append(invalid, key)
+		}
+	}
+
+	sort.Strings(invalid)
+
+	return invalid
+}
+
+func matches(h cmoresearch.Hit, query url.Values) bool {
+	if site := query.Get("site"); site != "" && !onSite(h, site) {
+		return false
+	}
+
+	if ids := query.Get("video_ids"); ids != "" {
+		a, ok := h.(*cmoresearch.Asset)
+		if !ok || !contains(strings.Split(ids, ","), a.VideoID) {
+			return false
+		}
+	}
+
+	if brandID := query.Get("brand_id"); brandID != "" && brandOf(h) != brandID {
+		return false
+	}
+
+	if season := query.Get("season"); season != "" {
+		a, ok := h.(*cmoresearch.Asset)
+		if !ok || strconv.Itoa(a.Season.Number) != season {
+			return false
+		}
+	}
+
+	return true
+}
+
+func onSite(h cmoresearch.Hit, site string) bool {
+	for _, e := range h.Subset().Events {
+		if e.Site == site {
+			return true
+		}
+	}
+	return false
+}
+
+func brandOf(h cmoresearch.Hit) string {
+	switch h := h.(type) {
+	case *cmoresearch.Asset:
+		return h.Brand.ID
+	case *cmoresearch.Series:
+		return h.BrandID
+	}
+	return ""
+}
+
+// document returns the JSON object of h, with its type set from its Go type if
+// empty.
+func document(h cmoresearch.Hit) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if h.Subset().Type == "" {
+		switch h.(type) {
+		case *cmoresearch.Asset:
+			doc["type"] = json.RawMessage(`"movie"`)
+		case *cmoresearch.Series:
+			doc["type"] = json.RawMessage(`"series"`)
+		}
+	}
+
+	return doc, nil
+}
+
+// sortDocs sorts docs by the value of field, numbers numerically and anything
+// else by its JSON. Documents without the field are sorted last.
+func sortDocs(docs []map[string]json.RawMessage, field string, desc bool) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		a, aok := docs[i][field]
+		b, bok := docs[j][field]
+
+		if !aok || !bok {
+			return aok && !bok
+		}
+
+		if desc {
+			a, b = b, a
+		}
+
+		an, aerr := strconv.ParseFloat(string(a), 64)
+		bn, berr := strconv.ParseFloat(string(b), 64)
+		if aerr == nil && berr == nil {
+			return an < bn
+		}
+
+		return string(a) < string(b)
+	})
+}
+
+func paginate(docs []map[string]json.RawMessage, page, pageSize int) []map[string]json.RawMessage {
+	start := (page - 1) * pageSize
+	if start >= len(docs) {
+		return []map[string]json.RawMessage{}
+	}
+
+	end := start + pageSize
+	if end > len(docs) {
+		end = len(docs)
+	}
+
+	return docs[start:end]
+}
+
+func selectFields(doc map[string]json.RawMessage, fields []string) map[string]json.RawMessage {
+	selected := make(map[string]json.RawMessage, len(fields))
+
+	for _, f := range fields {
+		if v, ok := doc[f]; ok {
+			selected[f] = v
+		}
+	}
+
+	return selected
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+
+	json.NewEncoder(w).Encode(struct {
+		Status  string `json:"status"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{"error", code, message})
+}
+
+func intParam(query url.Values, key string, def int) int {
+	if n, err := strconv.Atoi(query.Get(key)); err == nil {
+		return n
+	}
+	return def
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package cmoresearchtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	cmoresearch "github.com/TV4/cmoresearch-go"
+)
+
+func TestServer(t *testing.T) {
+	episode := func(videoID string, season, episode int, site string) *cmoresearch.Asset {
+		return &cmoresearch.Asset{
+			Type:          "episode",
+			VideoID:       videoID,
+			Brand:         cmoresearch.Brand{ID: "34515"},
+			Season:        cmoresearch.Season{Number: season},
+			EpisodeNumber: episode,
+			Events:        []cmoresearch.Event{{Site: site}},
+		}
+	}
+
+	srv := NewServer(
+		episode("3", 1, 3, "cmore.se"),
+		episode("1", 1, 1, "cmore.se"),
+		episode("2", 1, 2, "cmore.se"),
+		episode("4", 2, 1, "cmore.no"),
+		&cmoresearch.Series{BrandID: "34515", Events: []cmoresearch.Event{{Site: "cmore.se"}}},
+	)
+	defer srv.Close()
+
+	srv.Add(&cmoresearch.Asset{VideoID: "5", Brand: cmoresearch.Brand{ID: "1"}})
+
+	client := srv.Client()
+
+	search := func(t *testing.T, query url.Values) cmoresearch.Response {
+		t.Helper()
+
+		res, err := client.Search(context.Background(), query)
+		if err != nil {
+			t.Fatalf("Search: unexpected error %v", err)
+		}
+		return res
+	}
+
+	ids := func(res cmoresearch.Response) []string {
+		var ids []string
+		for _, h := range res.Hits {
+			ids = append(ids, h.Subset().ID)
+		}
+		return ids
+	}
+
+	for _, tc := range []struct {
+		description string
+		query       url.Values
+		total       int
+		ids         []string
+	}{
+		{"All", url.Values{}, 6, []string{"3", "1", "2", "4", "34515", "5"}},
+		{"VideoIDs", url.Values{"video_ids": {"2,5"}}, 2, []string{"2", "5"}},
+		{"Site", url.Values{"site": {"cmore.no"}}, 1, []string{"4"}},
+		{"BrandAndSeason", url.Values{"brand_id": {"34515"}, "season": {"1"}}, 3, []string{"3", "1", "2"}},
+		{"SortAsc", url.Values{"season": {"1"}, "sort_by": {"episode_number"}, "order": {"asc"}}, 3, []string{"1", "2", "3"}},
+		{"SortDesc", url.Values{"season": {"1"}, "sort_by": {"episode_number"}, "order": {"desc"}}, 3, []string{"3", "2", "1"}},
+		{"Page", url.Values{"season": {"1"}, "sort_by": {"episode_number"}, "page": {"2"}, "page_size": {"2"}}, 3, []string{"3"}},
+		{"PageOutOfRange", url.Values{"page": {"9"}}, 6, nil},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			res := search(t, tc.query)
+
+			if got, want := res.TotalHits, tc.total; got != want {
+				t.Errorf("res.TotalHits = %d, want %d", got, want)
+			}
+
+			got := ids(res)
+			if len(got) != len(tc.ids) {
+				t.Fatalf("ids = %v, want %v", got, tc.ids)
+			}
+			for i := range got {
+				if got[i] != tc.ids[i] {
+					t.Fatalf("ids = %v, want %v", got, tc.ids)
+				}
+			}
+		})
+	}
+
+	t.Run("Fields", func(t *testing.T) {
+		res := search(t, url.Values{"video_ids": {"1"}, "fields": {"video_id"}})
+
+		a, ok := res.Hits[0].(*cmoresearch.Asset)
+		if !ok {
+			t.Fatalf("res.Hits[0] is %T, want *cmoresearch.Asset", res.Hits[0])
+		}
+
+		if got, want := a.VideoID, "1"; got != want {
+			t.Errorf("a.VideoID = %q, want %q", got, want)
+		}
+
+		if got, want := a.EpisodeNumber, 0; got != want {
+			t.Errorf("a.EpisodeNumber = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		_, err := client.Search(context.Background(), url.Values{"site": {"example.com"}, "page": {"0"}})
+
+		var ae *cmoresearch.APIError
+		if !errors.As(err, &ae) {
+			t.Fatalf("err = %v, want *cmoresearch.APIError", err)
+		}
+
+		if got, want := ae.Code, 400; got != want {
+			t.Errorf("ae.Code = %d, want %d", got, want)
+		}
+
+		if got, want := ae.Message, "Invalid parameters: page, site"; got != want {
+			t.Errorf("ae.Message = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestServer_ConcurrentSearches(t *testing.T) {
+	var hits []cmoresearch.Hit
+	for i := 0; i < 1000; i++ {
+		hits = append(hits, &cmoresearch.Asset{Type: "movie", VideoID: strconv.Itoa(i), Events: []cmoresearch.Event{{Site: "cmore.no"}}})
+	}
+
+	srv := NewServer(hits...)
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	// serveSearch is called directly, as fault takes the write lock and so
+	// orders requests through ServeHTTP.
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/search?site=cmore.se", nil)
+
+			<-start
+			srv.serveSearch(rec, req)
+
+			if got, want := rec.Code, http.StatusOK; got != want {
+				t.Errorf("status = %d, want %d", got, want)
+			}
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+}
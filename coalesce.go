@@ -0,0 +1,131 @@
+package cmoresearch
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// flight is a request to the search service shared by concurrent searches.
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	res     Response
+	err     error
+}
+
+type noCoalescingKey struct{}
+
+// NoCoalescing is an option for Search to always send a request of its own,
+// instead of sharing the response of an identical search already in flight.
+func NoCoalescing() func(*http.Request) {
+	return func(r *http.Request) {
+		*r = *r.WithContext(context.WithValue(r.Context(), noCoalescingKey{}, true))
+	}
+}
+
+// coalescedFetch is like fetch, but concurrent calls for the same request
+// share a single request to the search service. Each caller gets its own copy
+// of the response.
+//
+// The shared request is only canceled when every caller waiting for it has
+// given up, so one caller canceling its context does not fail the others.
+func (c *Client) coalescedFetch(req *http.Request) (Response, error) {
+	ctx := req.Context()
+
+	if ctx.Value(noCoalescingKey{}) != nil {
+		return c.fetch(req)
+	}
+
+	key := flightKey(req)
+
+	c.flightsMu.Lock()
+	f, ok := c.flights[key]
+	if !ok {
+		var parent context.Context = detachedContext{ctx}
+		if deadline, ok := ctx.Deadline(); ok {
+			// Let the rate limiter fail the search right away rather
+			// than at its deadline.
+			parent = context.WithValue(parent, limitDeadlineKey{}, deadline)
+		}
+
+		flightCtx, cancel := context.WithCancel(parent)
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		c.flights[key] = f
+
+		go func() {
+			f.res, f.err = c.fetch(req.WithContext(flightCtx))
+
+			c.flightsMu.Lock()
+			if c.flights[key] == f {
+				delete(c.flights, key)
+			}
+			c.flightsMu.Unlock()
+
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	c.flightsMu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.res.copy(), f.err
+	case <-ctx.Done():
+		c.flightsMu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.cancel()
+			if c.flights[key] == f {
+				delete(c.flights, key)
+			}
+		}
+		c.flightsMu.Unlock()
+
+		return Response{Meta: Meta{RequestURL: req.URL}}, ctx.Err()
+	}
+}
+
+// flightKey identifies requests that can share a response: requests for the
+// same query with exactly the same headers, so that headers set by options,
+// e.g. SetRequestID, are never dropped by coalescing.
+func flightKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	b.WriteString(cacheKey(req.URL))
+
+	for _, name := range names {
+		for _, v := range req.Header[name] {
+			b.WriteString("\n")
+			b.WriteString(name)
+			b.WriteString(": ")
+			b.WriteString(v)
+		}
+	}
+
+	return b.String()
+}
+
+// detachedContext carries the values of its parent, but not its deadline or
+// cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (dc detachedContext) Value(key interface{}) interface{} {
+	return dc.parent.Value(key)
+}
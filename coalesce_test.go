@@ -0,0 +1,217 @@
+package cmoresearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingTransport blocks every request until release is closed.
+func blockingTransport(requests *int32, started chan<- struct{}, release <-chan struct{}) mockTransport {
+	return func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(requests, 1)
+		started <- struct{}{}
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":1,"assets":[{"type":"movie","video_id":"1","title_sv":"Solsidan"}]}`)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+}
+
+// waitForWaiters waits until n searches are waiting for the same flight.
+func waitForWaiters(t *testing.T, c *Client, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		c.flightsMu.Lock()
+		waiters := 0
+		for _, f := range c.flights {
+			waiters += f.waiters
+		}
+		c.flightsMu.Unlock()
+
+		if waiters == n {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d waiters", n)
+}
+
+func TestSearchCoalescing(t *testing.T) {
+	query := url.Values{"site": {"cmore.se"}}
+
+	t.Run("Shared", func(t *testing.T) {
+		var requests int32
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: blockingTransport(&requests, started, release)}))
+
+		const n = 5
+
+		var wg sync.WaitGroup
+		responses := make([]Response, n)
+		errs := make([]error, n)
+
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				responses[i], errs[i] = c.Search(context.Background(), query)
+			}(i)
+		}
+
+		<-started
+		waitForWaiters(t, c, n)
+		close(release)
+		wg.Wait()
+
+		if got, want := atomic.LoadInt32(&requests), int32(1); got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+
+		for i := 0; i < n; i++ {
+			if errs[i] != nil {
+				t.Fatalf("[%d] unexpected error: %v", i, errs[i])
+			}
+		}
+
+		responses[0].Hits[0].(*Asset).TitleSv = "modified"
+
+		for i := 1; i < n; i++ {
+			if got, want := responses[i].Hits[0].(*Asset).TitleSv, "Solsidan"; got != want {
+				t.Errorf("[%d] TitleSv = %q, want %q", i, got, want)
+			}
+		}
+	})
+
+	t.Run("DifferentHeaders", func(t *testing.T) {
+		var (
+			requests   int32
+			mu         sync.Mutex
+			requestIDs []string
+		)
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+
+		blocking := blockingTransport(&requests, started, release)
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requestIDs = append(requestIDs, r.Header.Get("X-Request-Id"))
+			mu.Unlock()
+			return blocking(r)
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		var wg sync.WaitGroup
+
+		for _, id := range []string{"a", "b"} {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				if _, err := c.Search(context.Background(), query, SetRequestID(id)); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}(id)
+		}
+
+		<-started
+		<-started
+		close(release)
+		wg.Wait()
+
+		sort.Strings(requestIDs)
+
+		if got, want := strings.Join(requestIDs, ","), "a,b"; got != want {
+			t.Errorf("request IDs = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("NoCoalescing", func(t *testing.T) {
+		var requests int32
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: blockingTransport(&requests, started, release)}))
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := c.Search(context.Background(), query, NoCoalescing()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+
+		<-started
+		<-started
+		close(release)
+		wg.Wait()
+
+		if got, want := atomic.LoadInt32(&requests), int32(2); got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("CallerCanceled", func(t *testing.T) {
+		var requests int32
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: blockingTransport(&requests, started, release)}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		canceledErr := make(chan error, 1)
+		go func() {
+			_, err := c.Search(ctx, query)
+			canceledErr <- err
+		}()
+
+		<-started
+
+		otherErr := make(chan error, 1)
+		go func() {
+			_, err := c.Search(context.Background(), query)
+			otherErr <- err
+		}()
+
+		waitForWaiters(t, c, 2)
+		cancel()
+
+		if got, want := <-canceledErr, context.Canceled; got != want {
+			t.Errorf("canceled search err = %v, want %v", got, want)
+		}
+
+		close(release)
+
+		if err := <-otherErr; err != nil {
+			t.Errorf("other search err = %v, want nil", err)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package cmoresearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// errMalformedObject is returned when scanning JSON that is not an object.
+var errMalformedObject = errors.New("json: malformed object")
+
+// scanObject calls fn with the key and the raw value of each member of the JSON
+// object in data, in order, until fn returns false. The values are not
+// decoded, and only keys containing escapes are unquoted, so scanning is much
+// cheaper than unmarshaling. The key and value share memory with data.
+func scanObject(data []byte, fn func(key, value []byte) bool) error {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return errMalformedObject
+	}
+
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return nil
+	}
+
+	for {
+		if i >= len(data) || data[i] != '"' {
+			return errMalformedObject
+		}
+
+		end, err := skipString(data, i)
+		if err != nil {
+			return err
+		}
+
+		key := data[i+1 : end-1]
+		if bytes.IndexByte(key, '\\') >= 0 {
+			var s string
+			if err := json.Unmarshal(data[i:end], &s); err != nil {
+				return err
+			}
+			key = []byte(s)
+		}
+
+		i = skipSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return errMalformedObject
+		}
+
+		start := skipSpace(data, i+1)
+
+		end, err = skipValue(data, start)
+		if err != nil {
+			return err
+		}
+
+		if !fn(key, data[start:end]) {
+			return nil
+		}
+
+		i = skipSpace(data, end)
+		if i >= len(data) {
+			return errMalformedObject
+		}
+
+		switch data[i] {
+		case ',':
+			i = skipSpace(data, i+1)
+		case '}':
+			return nil
+		default:
+			return errMalformedObject
+		}
+	}
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipString returns the index following the string starting at data[i].
+func skipString(data []byte, i int) (int, error) {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, errMalformedObject
+}
+
+// skipValue returns the index following the value starting at data[i].
+func skipValue(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return 0, errMalformedObject
+	}
+
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		var buf [16]byte
+		open := buf[:0]
+
+		for j := i; j < len(data); j++ {
+			switch c := data[j]; c {
+			case '"':
+				end, err := skipString(data, j)
+				if err != nil {
+					return 0, err
+				}
+				j = end - 1
+			case '{', '[':
+				open = append(open, c)
+			case '}', ']':
+				// The closing brackets follow their opening ones by two
+				// in ASCII.
+				if last := open[len(open)-1]; c != last+2 {
+					return 0, errMalformedObject
+				}
+				if open = open[:len(open)-1]; len(open) == 0 {
+					return j + 1, nil
+				}
+			}
+		}
+		return 0, errMalformedObject
+	}
+
+	j := i
+	for j < len(data) && isLiteralByte(data[j]) {
+		j++
+	}
+
+	if j == i {
+		return 0, errMalformedObject
+	}
+
+	return j, nil
+}
+
+// isLiteralByte tells whether c may be part of a number, true, false or null.
+func isLiteralByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c == '-' || c == '+' || c == '.' || c == 'E'
+}
+
+var stringType = reflect.TypeOf("")
+
+// hitType returns the type field of the hit in data without decoding the rest
+// of the hit. Like encoding/json, it matches the key case-insensitively and
+// keeps the last match. It returns an empty string if the hit is not an
+// object, or has no type or a null one.
+func hitType(data []byte) (string, error) {
+	if i := skipSpace(data, 0); i >= len(data) || data[i] != '{' {
+		return "", nil
+	}
+
+	var value []byte
+
+	err := scanObject(data, func(key, v []byte) bool {
+		if bytes.EqualFold(key, []byte("type")) {
+			value = v
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case value == nil, string(value) == "null":
+		return "", nil
+	case value[0] != '"':
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return "", err
+		}
+		return "", &json.UnmarshalTypeError{Value: jsonType(v), Type: stringType, Field: "type"}
+	case bytes.IndexByte(value, '\\') >= 0:
+		var s string
+		err := json.Unmarshal(value, &s)
+		return s, err
+	}
+
+	return string(value[1 : len(value)-1]), nil
+}
@@ -0,0 +1,273 @@
+package cmoresearch
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// benchmarkAsset and benchmarkSeries are hits as returned by the search
+// service, trimmed to a realistic subset of fields.
+const (
+	benchmarkAsset = `{
+		"type": "episode",
+		"video_id": "2222333",
+		"vman_id": "a1b2c3",
+		"content_api_id": "ca-2222333",
+		"content_api_season_id": "ca-season-1",
+		"content_api_series_id": "ca-series-34515",
+		"content_source": "cmore",
+		"parent_video_id": "",
+		"title_sv": "Avsnitt 2",
+		"title_nb": "Episode 2",
+		"title_da": "Afsnit 2",
+		"title_fi": "Jakso 2",
+		"description_tiny_sv": "Alex och Anna flyttar in.",
+		"description_short_sv": "Alex och Anna flyttar till Saltsjöbaden och möter grannarna.",
+		"description_medium_sv": "Alex och Anna flyttar till Saltsjöbaden där de möter grannarna Ove och Mickan, som genast vill bli deras bästa vänner.",
+		"description_long_sv": "Alex och Anna flyttar till Saltsjöbaden där de möter grannarna Ove och Mickan, som genast vill bli deras bästa vänner. Alex försöker smälta in i förorten, men allt går inte som planerat när en middagsbjudning spårar ur.",
+		"description_short_nb": "Alex og Anna flytter til Saltsjöbaden og møter naboene.",
+		"description_short_da": "Alex og Anna flytter til Saltsjöbaden og møder naboerne.",
+		"description_short_fi": "Alex ja Anna muuttavat Saltsjöbadeniin ja tapaavat naapurit.",
+		"genre_description_sv": "Komedi",
+		"genre_description_nb": "Komedie",
+		"genres": [{"main": "Comedy", "sub": ["Sitcom", "Drama"]}],
+		"keywords_sv": [{"nid": "komedi", "text": "Komedi"}, {"nid": "forort", "text": "Förort"}],
+		"country": ["SE"],
+		"spoken_languages": ["sv"],
+		"production_year": "2010",
+		"studio": "FLX",
+		"duration": 1320,
+		"episode_number": 2,
+		"drm_restrictions": true,
+		"items_published": true,
+		"live": false,
+		"timestamp": "20190301120000",
+		"credits": [
+			{"function": "actor", "nid": "felix-herngren", "name": "Felix Herngren", "rolename": "Fredrik"},
+			{"function": "actor", "nid": "josephine-bornebusch", "name": "Josephine Bornebusch", "rolename": "Anna"},
+			{"function": "director", "nid": "felix-herngren", "name": "Felix Herngren", "rolename": ""}
+		],
+		"events": [
+			{
+				"site": "cmore.se",
+				"device_types": ["tve_web", "tve_mobile", "tve_tablet", "tve_smarttv", "tve_chromecast", "tve_appletv"],
+				"product_groups": ["premium"],
+				"products": ["cmore_premium", "cmore_total"],
+				"start_time": "2019-01-01T00:00:00Z",
+				"end_time": "2030-01-01T00:00:00Z",
+				"publish_time": "2018-12-24T12:00:00Z",
+				"live_published": false,
+				"on_demand_published": true
+			},
+			{
+				"site": "cmore.no",
+				"device_types": ["tve_web", "tve_mobile"],
+				"product_groups": ["premium"],
+				"products": ["cmore_premium"],
+				"start_time": "2019-02-01T00:00:00Z",
+				"end_time": "2030-01-01T00:00:00Z",
+				"publish_time": "2019-01-24T12:00:00Z",
+				"live_published": false,
+				"on_demand_published": true
+			}
+		],
+		"external_references": [{"locator": "imdb", "type": "episode", "value": "tt1634555"}],
+		"landscape": {"caption": "Solsidan", "copyright": "C More", "url": "https://img.example.com/landscape.jpg", "localizations": [{"caption": "Solsidan", "copyright": "C More", "language": "nb", "url": "https://img.example.com/landscape-nb.jpg"}]},
+		"poster": {"caption": "Solsidan", "copyright": "C More", "url": "https://img.example.com/poster.jpg"},
+		"brand": {
+			"id": "34515",
+			"title_sv": "Solsidan",
+			"title_nb": "Solsidan",
+			"description_short_sv": "Komediserie om livet i Saltsjöbaden.",
+			"genre_description_sv": "Komedi",
+			"country": ["SE"],
+			"landscape": {"caption": "Solsidan", "copyright": "C More", "url": "https://img.example.com/brand.jpg"}
+		},
+		"season": {
+			"id": "34516",
+			"season_number": 1,
+			"number_of_episodes": 10,
+			"title_sv": "Säsong 1",
+			"description_short_sv": "Första säsongen av Solsidan."
+		},
+		"parental_ratings": [{"country": "SE", "system": "SMFB", "value": "7"}, {"country": "NO", "system": "Medietilsynet", "value": "6"}],
+		"publication_rights": {"location_rights": {"product": "cmore", "location_restrictions": {"include_countries": ["SE", "NO"]}}},
+		"original_title": {"language": "sv", "text": "Solsidan", "type": "original"},
+		"tags": {"theme": ["family", "suburb"], "mood": ["funny"]},
+		"mlt_nids": ["solsidan", "bonusfamiljen"],
+		"play_count": 123456
+	}`
+
+	benchmarkSeries = `{
+		"type": "series",
+		"brand_id": "34515",
+		"id": "34515",
+		"content_api_series_id": "ca-series-34515",
+		"content_source": "cmore",
+		"title_sv": "Solsidan",
+		"title_nb": "Solsidan",
+		"title_da": "Solsidan",
+		"title_fi": "Solsidan",
+		"description_short_sv": "Komediserie om livet i Saltsjöbaden.",
+		"description_long_sv": "Komediserie om Alex och Anna som flyttar till Saltsjöbaden där de möter grannarna Ove och Mickan.",
+		"description_short_nb": "Komiserie fra Saltsjöbaden.",
+		"genre_description_sv": "Komedi",
+		"genres": [{"main": "Comedy", "sub": ["Sitcom"]}],
+		"keywords_sv": [{"nid": "komedi", "text": "Komedi"}],
+		"country": ["SE"],
+		"spoken_languages": ["sv"],
+		"studio": "FLX",
+		"scripted": true,
+		"timestamp": "20190301120000",
+		"seasons": [1, 2, 3, 4, 5],
+		"seasons_cmore_se": [1, 2, 3, 4, 5],
+		"seasons_cmore_no": [1, 2, 3],
+		"credits": [{"function": "actor", "nid": "felix-herngren", "name": "Felix Herngren", "rolename": "Fredrik"}],
+		"events": [
+			{
+				"site": "cmore.se",
+				"device_types": ["tve_web", "tve_mobile", "tve_tablet"],
+				"product_groups": ["premium"],
+				"products": ["cmore_premium"],
+				"start_time": "2019-01-01T00:00:00Z",
+				"end_time": "2030-01-01T00:00:00Z",
+				"publish_time": "2018-12-24T12:00:00Z",
+				"live_published": false,
+				"on_demand_published": true
+			}
+		],
+		"landscape": {"caption": "Solsidan", "copyright": "C More", "url": "https://img.example.com/landscape.jpg"},
+		"poster": {"caption": "Solsidan", "copyright": "C More", "url": "https://img.example.com/poster.jpg"},
+		"parental_ratings": [{"country": "SE", "system": "SMFB", "value": "7"}],
+		"tags": {"theme": ["family"]},
+		"follow_count": 4321
+	}`
+)
+
+func TestScanObject(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want string
+		err  bool
+	}{
+		{`{}`, ``, false},
+		{` { "a" : 1 } `, `a=1;`, false},
+		{`{"a":"x,}\"y","b":[1,{"c":"]"}],"d":{"e":[]},"f":true,"g":null,"h":-1.5e3}`, `a="x,}\"y";b=[1,{"c":"]"}];d={"e":[]};f=true;g=null;h=-1.5e3;`, false},
+		{`{"t\u0079pe":"movie"}`, `type="movie";`, false},
+		{`[]`, ``, true},
+		{`{"a":1`, `a=1;`, true},
+		{`{"a" 1}`, ``, true},
+		{`{"a":}`, ``, true},
+		{`{"a":"1}`, ``, true},
+		{`{"a":[1}`, ``, true},
+		{`{"a":1;"b":2}`, `a=1;`, true},
+	} {
+		var got strings.Builder
+
+		err := scanObject([]byte(tc.data), func(key, value []byte) bool {
+			fmt.Fprintf(&got, "%s=%s;", key, value)
+			return true
+		})
+
+		if (err != nil) != tc.err {
+			t.Errorf("scanObject(%s) err = %v, want error %t", tc.data, err, tc.err)
+		}
+
+		if got.String() != tc.want {
+			t.Errorf("scanObject(%s) = %s, want %s", tc.data, got.String(), tc.want)
+		}
+	}
+}
+
+func TestHitType(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want string
+		err  string
+	}{
+		{`{"video_id":"1","type":"movie"}`, "movie", ""},
+		{`{"Type":"series","type":"movie"}`, "movie", ""},
+		{`{"type":"movie","TYPE":"series"}`, "series", ""},
+		{`{"type":"mo\u0076ie"}`, "movie", ""},
+		{`{"type":null}`, "", ""},
+		{`{"video_id":"1"}`, "", ""},
+		{`null`, "", ""},
+		{` []`, "", ""},
+		{`{"type":{"a":1}}`, "", "json: cannot unmarshal object into Go struct field .type of type string"},
+		{`{"type":1}`, "", "json: cannot unmarshal number into Go struct field .type of type string"},
+	} {
+		got, err := hitType([]byte(tc.data))
+
+		if tc.err == "" && err != nil {
+			t.Errorf("hitType(%s): unexpected error %v", tc.data, err)
+			continue
+		}
+
+		if tc.err != "" && (err == nil || err.Error() != tc.err) {
+			t.Errorf("hitType(%s) err = %v, want %q", tc.data, err, tc.err)
+			continue
+		}
+
+		if got != tc.want {
+			t.Errorf("hitType(%s) = %q, want %q", tc.data, got, tc.want)
+		}
+	}
+}
+
+func BenchmarkDecodeHit(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		raw  string
+	}{
+		{"Asset", benchmarkAsset},
+		{"Series", benchmarkSeries},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			raw := []byte(bc.raw)
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(raw)))
+
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeHit(raw, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMakeResponse(b *testing.B) {
+	hits := make([]string, 100)
+	for i := range hits {
+		if i%10 == 0 {
+			hits[i] = benchmarkSeries
+		} else {
+			hits[i] = benchmarkAsset
+		}
+	}
+
+	body := []byte(fmt.Sprintf(`{"total_hits":100,"assets":[%s]}`, strings.Join(hits, ",")))
+
+	req := httptest.NewRequest(http.MethodGet, "/search", nil)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+
+	for i := 0; i < b.N; i++ {
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+
+		if _, err := makeResponse(req, resp, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
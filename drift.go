@@ -0,0 +1,247 @@
+package cmoresearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemaDriftKind is the kind of difference between a hit received from the
+// search service and the type it is decoded into.
+type SchemaDriftKind string
+
+// Kinds of schema drift.
+const (
+	// DriftUnknownField is a field not mapped to the hit type.
+	DriftUnknownField SchemaDriftKind = "unknown_field"
+
+	// DriftTypeMismatch is a field with a JSON type that cannot be decoded
+	// into the Go type of the field, or a string that is not a valid time
+	// in a time field.
+	DriftTypeMismatch SchemaDriftKind = "type_mismatch"
+
+	// DriftUnknownType is a hit of a type without a registered factory.
+	DriftUnknownType SchemaDriftKind = "unknown_type"
+)
+
+// SchemaDriftReport describes a field of a hit that does not match the type
+// the hit is decoded into.
+type SchemaDriftReport struct {
+	// HitType is the type field of the hit.
+	HitType string
+
+	// Path is the path to the field, e.g. "brand.title_sv" or
+	// "events[0].start_time". It is empty for DriftUnknownType.
+	Path string
+
+	Kind SchemaDriftKind
+
+	// Observed is the JSON type of the field: object, array, string,
+	// number, boolean or null.
+	Observed string
+}
+
+func (r SchemaDriftReport) String() string {
+	if r.Path == "" {
+		return fmt.Sprintf("%s: %s (%s)", r.HitType, r.Kind, r.Observed)
+	}
+	return fmt.Sprintf("%s.%s: %s (%s)", r.HitType, r.Path, r.Kind, r.Observed)
+}
+
+// SchemaDriftError is returned by Search when strict decoding is configured to
+// fail on schema drift.
+type SchemaDriftError struct {
+	Reports []SchemaDriftReport
+}
+
+func (e *SchemaDriftError) Error() string {
+	if len(e.Reports) == 1 {
+		return fmt.Sprintf("schema drift: %s", e.Reports[0])
+	}
+	return fmt.Sprintf("schema drift: %s (and %d more)", e.Reports[0], len(e.Reports)-1)
+}
+
+// StrictDecoding configures strict decoding of hits.
+type StrictDecoding struct {
+	// OnDrift, if set, is called for every schema drift found.
+	OnDrift func(SchemaDriftReport)
+
+	// FailOnDrift makes Search fail with a *SchemaDriftError if any schema
+	// drift is found.
+	FailOnDrift bool
+}
+
+// SetStrictDecoding is an option to make the client check every hit for fields
+// unknown to, or of another type than in, the type the hit is decoded into.
+// Schema drift found is reported in Meta.SchemaDrift and to sd.OnDrift.
+//
+// Fields with mismatching types are reported rather than failing the search,
+// and left at their zero value, unless sd.FailOnDrift is set.
+func SetStrictDecoding(sd StrictDecoding) func(*Client) {
+	return func(c *Client) {
+		c.strictDecoding = &sd
+	}
+}
+
+// detectDrift compares the JSON of a hit with type t.
+func detectDrift(hitType string, data []byte, t reflect.Type) []SchemaDriftReport {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+
+	var reports []SchemaDriftReport
+	walkDrift(&reports, hitType, "", v, t)
+	return reports
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func walkDrift(reports *[]SchemaDriftReport, hitType, path string, v interface{}, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if v == nil {
+		return
+	}
+
+	mismatch := func() {
+		*reports = append(*reports, SchemaDriftReport{
+			HitType:  hitType,
+			Path:     path,
+			Kind:     DriftTypeMismatch,
+			Observed: jsonType(v),
+		})
+	}
+
+	if t == timeType {
+		if s, ok := v.(string); !ok {
+			mismatch()
+		} else if _, err := time.Parse(time.RFC3339, s); err != nil {
+			mismatch()
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+
+		fields := structFields(t)
+
+		for k, fv := range obj {
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+
+			f, ok := fields[strings.ToLower(k)]
+			if !ok {
+				*reports = append(*reports, SchemaDriftReport{
+					HitType:  hitType,
+					Path:     fieldPath,
+					Kind:     DriftUnknownField,
+					Observed: jsonType(fv),
+				})
+				continue
+			}
+
+			walkDrift(reports, hitType, fieldPath, fv, f.Type)
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+
+		for i, ev := range arr {
+			walkDrift(reports, hitType, path+"["+strconv.Itoa(i)+"]", ev, t.Elem())
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+
+		for k, ev := range obj {
+			walkDrift(reports, hitType, path+"."+k, ev, t.Elem())
+		}
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			mismatch()
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			mismatch()
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := v.(float64); !ok || n != float64(int64(n)) {
+			mismatch()
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := v.(float64); !ok {
+			mismatch()
+		}
+	}
+}
+
+var structFieldsCache sync.Map // map[reflect.Type]map[string]reflect.StructField
+
+// structFields returns the exported fields of struct type t by lower cased
+// JSON name.
+func structFields(t reflect.Type) map[string]reflect.StructField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.StructField)
+	}
+
+	fields := make(map[string]reflect.StructField, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[strings.ToLower(name)] = f
+	}
+
+	structFieldsCache.Store(t, fields)
+
+	return fields
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
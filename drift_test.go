@@ -0,0 +1,167 @@
+package cmoresearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	data := `{
+		"type": "movie",
+		"video_id": "123",
+		"title_sv": {"text": "Solsidan"},
+		"episode_number": "1",
+		"duration": 1.5,
+		"live": true,
+		"new_field": [1, 2],
+		"brand": {"id": "b1", "brand_color": "red"},
+		"events": [{"site": "cmore.se", "start_time": 1551441600}],
+		"tags": {"foo": ["bar", 1]},
+		"season": null,
+		"live_event_end": "yesterday"
+	}`
+
+	reports := detectDrift("movie", []byte(data), reflect.TypeOf(&Asset{}))
+
+	var got []string
+	for _, r := range reports {
+		got = append(got, r.String())
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"movie.brand.brand_color: unknown_field (string)",
+		"movie.duration: type_mismatch (number)",
+		"movie.episode_number: type_mismatch (string)",
+		"movie.events[0].start_time: type_mismatch (number)",
+		"movie.live_event_end: type_mismatch (string)",
+		"movie.new_field: unknown_field (array)",
+		"movie.tags.foo[1]: type_mismatch (number)",
+		"movie.title_sv: type_mismatch (object)",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reports =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSearchStrictDecoding(t *testing.T) {
+	body := `{"total_hits":2,"assets":[
+		{"type":"movie","video_id":"1","title_sv":{"malformed":true},"title_nb":"Solsiden"},
+		{"type":"person","id":"p1"}
+	]}`
+
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+
+	t.Run("Report", func(t *testing.T) {
+		var reported []SchemaDriftReport
+
+		c := NewClient(
+			SetBaseURL("/"),
+			SetHTTPClient(&http.Client{Transport: mockT}),
+			SetStrictDecoding(StrictDecoding{
+				OnDrift: func(r SchemaDriftReport) { reported = append(reported, r) },
+			}),
+		)
+
+		res, err := c.Search(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(res.Hits), 2; got != want {
+			t.Fatalf("len(res.Hits) = %d, want %d", got, want)
+		}
+
+		if got, want := res.Hits[0].(*Asset).TitleNb, "Solsiden"; got != want {
+			t.Errorf("TitleNb = %q, want %q", got, want)
+		}
+
+		want := []SchemaDriftReport{
+			{HitType: "movie", Path: "title_sv", Kind: DriftTypeMismatch, Observed: "object"},
+			{HitType: "person", Kind: DriftUnknownType, Observed: "object"},
+		}
+
+		if !reflect.DeepEqual(res.Meta.SchemaDrift, want) {
+			t.Errorf("res.Meta.SchemaDrift = %v, want %v", res.Meta.SchemaDrift, want)
+		}
+
+		if !reflect.DeepEqual(reported, want) {
+			t.Errorf("reported = %v, want %v", reported, want)
+		}
+	})
+
+	t.Run("FailOnDrift", func(t *testing.T) {
+		c := NewClient(
+			SetBaseURL("/"),
+			SetHTTPClient(&http.Client{Transport: mockT}),
+			SetStrictDecoding(StrictDecoding{FailOnDrift: true}),
+		)
+
+		_, err := c.Search(context.Background(), nil)
+
+		sde, ok := err.(*SchemaDriftError)
+		if !ok {
+			t.Fatalf("error is a %T (%v), want a %T", err, err, &SchemaDriftError{})
+		}
+
+		if got, want := sde.Error(), "schema drift: movie.title_sv: type_mismatch (object) (and 1 more)"; got != want {
+			t.Errorf("sde.Error() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("NotStrict", func(t *testing.T) {
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		if _, err := c.Search(context.Background(), nil); err == nil {
+			t.Fatal("got nil, want error")
+		}
+	})
+	t.Run("InvalidTime", func(t *testing.T) {
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":1,"assets":[{"type":"movie","live_event_end":"yesterday","title_nb":"Solsiden"}]}`)),
+				Header:     make(http.Header),
+				StatusCode: http.StatusOK,
+			}
+			resp.Header.Add("Content-Type", "application/json")
+			return resp, nil
+		}
+
+		c := NewClient(
+			SetBaseURL("/"),
+			SetHTTPClient(&http.Client{Transport: mockT}),
+			SetStrictDecoding(StrictDecoding{}),
+		)
+
+		res, err := c.Search(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := res.Hits[0].(*Asset).TitleNb, "Solsiden"; got != want {
+			t.Errorf("TitleNb = %q, want %q", got, want)
+		}
+
+		want := []SchemaDriftReport{
+			{HitType: "movie", Path: "live_event_end", Kind: DriftTypeMismatch, Observed: "string"},
+		}
+
+		if !reflect.DeepEqual(res.Meta.SchemaDrift, want) {
+			t.Errorf("res.Meta.SchemaDrift = %v, want %v", res.Meta.SchemaDrift, want)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package cmoresearch
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	siteCountriesMu sync.RWMutex
+	siteCountries   = map[Site]string{
+		SiteSE: "SE",
+		SiteNO: "NO",
+		SiteDK: "DK",
+		SiteFI: "FI",
+	}
+)
+
+// RegisterSiteCountry registers the country that site serves, as an ISO 3166-1
+// alpha-2 code. An empty country makes the site serve no country, as do sites
+// never registered. The sites of this package serve the country of their
+// top-level domain by default.
+func RegisterSiteCountry(site Site, country string) {
+	siteCountriesMu.Lock()
+	defer siteCountriesMu.Unlock()
+
+	m := make(map[Site]string, len(siteCountries)+1)
+	for s, c := range siteCountries {
+		m[s] = c
+	}
+
+	if country == "" {
+		delete(m, site)
+	} else {
+		m[site] = strings.ToUpper(country)
+	}
+
+	siteCountries = m
+}
+
+// AllowedIn tells whether hit may be shown in country, an ISO 3166-1 alpha-2
+// code. The country must be among the included countries of the location
+// rights of the hit, if it has any, and be served by the site of at least one
+// of its events, if it has any. Only assets carry location rights; other hits
+// are checked against their events alone.
+func AllowedIn(hit Hit, country string) bool {
+	country = strings.ToUpper(country)
+
+	if a, ok := hit.(*Asset); ok {
+		include := a.PublicationRights.LocationRights.LocationRestrictions.IncludeCountries
+		if len(include) > 0 && !containsFold(include, country) {
+			return false
+		}
+	}
+
+	events := hit.Subset().Events
+	if len(events) == 0 {
+		return true
+	}
+
+	siteCountriesMu.RLock()
+	countries := siteCountries
+	siteCountriesMu.RUnlock()
+
+	for _, e := range events {
+		if countries[Site(e.Site)] == country {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowedIn tells whether the asset may be shown in country. See AllowedIn.
+func (a *Asset) AllowedIn(country string) bool {
+	return AllowedIn(a, country)
+}
+
+// Filter returns a copy of r with only the hits for which keep returns true.
+// TotalHits is left as reported by the search service, and the hits kept are
+// shared with r.
+func (r Response) Filter(keep func(Hit) bool) Response {
+	hits := make([]Hit, 0, len(r.Hits))
+
+	for _, h := range r.Hits {
+		if keep(h) {
+			hits = append(hits, h)
+		}
+	}
+
+	r.Hits = hits
+
+	return r
+}
+
+// FilterCountry returns a copy of r without the hits that may not be shown in
+// country. See AllowedIn and Filter.
+func (r Response) FilterCountry(country string) Response {
+	return r.Filter(func(h Hit) bool { return AllowedIn(h, country) })
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package cmoresearch
+
+import "testing"
+
+func TestAllowedIn(t *testing.T) {
+	rights := func(countries ...string) PublicationRights {
+		return PublicationRights{LocationRights: LocationRights{
+			LocationRestrictions: LocationRestrictions{IncludeCountries: countries},
+		}}
+	}
+
+	for _, tc := range []struct {
+		description string
+		hit         Hit
+		country     string
+		want        bool
+	}{
+		{"Unrestricted", &Asset{}, "SE", true},
+		{"IncludedCountry", &Asset{PublicationRights: rights("SE", "NO")}, "NO", true},
+		{"ExcludedCountry", &Asset{PublicationRights: rights("SE", "NO")}, "DK", false},
+		{"LowerCase", &Asset{PublicationRights: rights("se")}, "se", true},
+		{"EventSite", &Asset{Events: []Event{{Site: "cmore.no"}, {Site: "cmore.dk"}}}, "DK", true},
+		{"NoEventSite", &Asset{Events: []Event{{Site: "cmore.no"}}}, "SE", false},
+		{"UnknownEventSite", &Asset{Events: []Event{{Site: "example.com"}}}, "SE", false},
+		{"RightsAndEvents", &Asset{PublicationRights: rights("FI"), Events: []Event{{Site: "cmore.se"}}}, "SE", false},
+		{"Series", &Series{Events: []Event{{Site: "cmore.fi"}}}, "FI", true},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := AllowedIn(tc.hit, tc.country); got != tc.want {
+				t.Errorf("AllowedIn(%q) = %t, want %t", tc.country, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRegisterSiteCountry(t *testing.T) {
+	orig := siteCountries
+	defer func() { siteCountries = orig }()
+
+	RegisterSiteCountry("example.com", "se")
+	RegisterSiteCountry(SiteFI, "")
+
+	if !AllowedIn(&Asset{Events: []Event{{Site: "example.com"}}}, "SE") {
+		t.Error("example.com: AllowedIn(SE) = false, want true")
+	}
+
+	if AllowedIn(&Asset{Events: []Event{{Site: "cmore.fi"}}}, "FI") {
+		t.Error("cmore.fi: AllowedIn(FI) = true, want false")
+	}
+
+	if got, want := orig[SiteFI], "FI"; got != want {
+		t.Errorf("previous country of %s changed to %q, want %q", SiteFI, got, want)
+	}
+}
+
+func TestResponse_FilterCountry(t *testing.T) {
+	r := Response{
+		TotalHits: 10,
+		Hits: []Hit{
+			&Asset{VideoID: "se", Events: []Event{{Site: "cmore.se"}}},
+			&Asset{VideoID: "no", Events: []Event{{Site: "cmore.no"}}},
+			&Series{BrandID: "any"},
+		},
+	}
+
+	filtered := r.FilterCountry("SE")
+
+	if got, want := filtered.TotalHits, 10; got != want {
+		t.Errorf("filtered.TotalHits = %d, want %d", got, want)
+	}
+
+	if got, want := len(filtered.Hits), 2; got != want {
+		t.Fatalf("len(filtered.Hits) = %d, want %d", got, want)
+	}
+
+	if got, want := filtered.Hits[0].Subset().ID, "se"; got != want {
+		t.Errorf("filtered.Hits[0] ID = %q, want %q", got, want)
+	}
+
+	if got, want := filtered.Hits[1].Subset().ID, "any"; got != want {
+		t.Errorf("filtered.Hits[1] ID = %q, want %q", got, want)
+	}
+
+	if got, want := len(r.Hits), 3; got != want {
+		t.Errorf("len(r.Hits) = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package cmoresearch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const defaultIteratorPageSize = 100
+
+// Iterator iterates over all hits of a search, fetching pages lazily. Use
+// Client.SearchAll to create an Iterator.
+type Iterator struct {
+	client  *Client
+	ctx     context.Context
+	query   url.Values
+	options []func(*http.Request)
+
+	page     int
+	fetched  int
+	hits     []Hit
+	hit      Hit
+	seen     map[string]bool
+	done     bool
+	err      error
+	response Response
+}
+
+// SearchAll returns an Iterator over all hits matching query. Pages are
+// fetched from the search service as the iterator advances, starting at the
+// page given in query (or the first page), until Response.TotalHits hits have
+// been fetched. If the page_size parameter is not set, 100 hits are fetched per
+// page.
+//
+// Hits seen on a previous page, as identified by Subset().ID, are skipped so
+// that the iterator does not return duplicates when the catalog changes
+// between pages.
+func (c *Client) SearchAll(ctx context.Context, query url.Values, options ...func(r *http.Request)) *Iterator {
+	q := make(url.Values, len(query))
+	for key, values := range query {
+		q[key] = append([]string(nil), values...)
+	}
+
+	page := 1
+	if p, err := strconv.Atoi(q.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	pageSize, err := strconv.Atoi(q.Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultIteratorPageSize
+		q.Set("page_size", strconv.Itoa(pageSize))
+	}
+
+	return &Iterator{
+		client:  c,
+		ctx:     ctx,
+		query:   q,
+		options: options,
+		page:    page,
+		fetched: (page - 1) * pageSize,
+		seen:    make(map[string]bool),
+	}
+}
+
+// Next advances the iterator to the next hit, which is then available through
+// Hit. It returns false when there are no more hits or an error occurred, in
+// which case Err returns the error.
+func (it *Iterator) Next() bool {
+	it.hit = nil
+
+	for it.err == nil {
+		if len(it.hits) > 0 {
+			h := it.hits[0]
+			it.hits = it.hits[1:]
+
+			if id := h.Subset().ID; id != "" {
+				if it.seen[id] {
+					continue
+				}
+				it.seen[id] = true
+			}
+
+			it.hit = h
+			return true
+		}
+
+		if it.done {
+			return false
+		}
+
+		it.fetch()
+	}
+
+	return false
+}
+
+func (it *Iterator) fetch() {
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return
+	}
+
+	it.query.Set("page", strconv.Itoa(it.page))
+
+	res, err := it.client.Search(it.ctx, it.query, it.options...)
+	it.response = res
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.page++
+	it.fetched += len(res.Hits)
+	it.hits = res.Hits
+
+	if len(res.Hits) == 0 || it.fetched >= res.TotalHits {
+		it.done = true
+	}
+}
+
+// Hit returns the current hit.
+func (it *Iterator) Hit() Hit {
+	return it.hit
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Response returns the response of the most recently fetched page.
+func (it *Iterator) Response() Response {
+	return it.response
+}
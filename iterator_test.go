@@ -0,0 +1,202 @@
+package cmoresearch
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func pagedTransport(t *testing.T, pages map[string]string, requested *[]string) mockTransport {
+	return func(r *http.Request) (*http.Response, error) {
+		page := r.URL.Query().Get("page")
+		*requested = append(*requested, page)
+
+		body, ok := pages[page]
+		if !ok {
+			t.Fatalf("unexpected request for page %q", page)
+		}
+
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+}
+
+func TestSearchAll(t *testing.T) {
+	t.Run("AllPages", func(t *testing.T) {
+		var requested []string
+
+		mockT := pagedTransport(t, map[string]string{
+			"1": `{"total_hits":5,"assets":[{"type":"movie","video_id":"1"},{"type":"movie","video_id":"2"}]}`,
+			"2": `{"total_hits":5,"assets":[{"type":"movie","video_id":"3"},{"type":"series","brand_id":"4"}]}`,
+			"3": `{"total_hits":5,"assets":[{"type":"movie","video_id":"5"}]}`,
+		}, &requested)
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		it := c.SearchAll(context.Background(), url.Values{"page_size": {"2"}})
+
+		var ids []string
+		for it.Next() {
+			ids = append(ids, it.Hit().Subset().ID)
+		}
+
+		if err := it.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := strings.Join(ids, ","), "1,2,3,4,5"; got != want {
+			t.Errorf("ids = %q, want %q", got, want)
+		}
+
+		if got, want := strings.Join(requested, ","), "1,2,3"; got != want {
+			t.Errorf("requested pages = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		var requested []string
+
+		mockT := pagedTransport(t, map[string]string{
+			"1": `{"total_hits":4,"assets":[{"type":"movie","video_id":"1"},{"type":"movie","video_id":"2"}]}`,
+			"2": `{"total_hits":4,"assets":[{"type":"movie","video_id":"2"},{"type":"movie","video_id":"3"}]}`,
+		}, &requested)
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		it := c.SearchAll(context.Background(), url.Values{"page_size": {"2"}})
+
+		var ids []string
+		for it.Next() {
+			ids = append(ids, it.Hit().Subset().ID)
+		}
+
+		if err := it.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := strings.Join(ids, ","), "1,2,3"; got != want {
+			t.Errorf("ids = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("EmptyPage", func(t *testing.T) {
+		var requested []string
+
+		mockT := pagedTransport(t, map[string]string{
+			"1": `{"total_hits":10,"assets":[{"type":"movie","video_id":"1"}]}`,
+			"2": `{"total_hits":10,"assets":[]}`,
+		}, &requested)
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		it := c.SearchAll(context.Background(), url.Values{"page_size": {"1"}})
+
+		n := 0
+		for it.Next() {
+			n++
+		}
+
+		if err := it.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := n, 1; got != want {
+			t.Errorf("n = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("DefaultPageSize", func(t *testing.T) {
+		var gotPageSize string
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			gotPageSize = r.URL.Query().Get("page_size")
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":0,"assets":[]}`)),
+				Header:     make(http.Header),
+				StatusCode: http.StatusOK,
+			}
+			resp.Header.Add("Content-Type", "application/json")
+			return resp, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		query := url.Values{}
+
+		it := c.SearchAll(context.Background(), query)
+		for it.Next() {
+		}
+
+		if got, want := gotPageSize, fmt.Sprint(defaultIteratorPageSize); got != want {
+			t.Errorf("page_size = %q, want %q", got, want)
+		}
+
+		if len(query) != 0 {
+			t.Errorf("query was modified: %v", query)
+		}
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		var requested []string
+
+		mockT := pagedTransport(t, map[string]string{
+			"1": `{"total_hits":4,"assets":[{"type":"movie","video_id":"1"},{"type":"movie","video_id":"2"}]}`,
+		}, &requested)
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		it := c.SearchAll(ctx, url.Values{"page_size": {"2"}})
+
+		n := 0
+		for it.Next() {
+			n++
+			if n == 2 {
+				cancel()
+			}
+		}
+
+		if got, want := it.Err(), context.Canceled; got != want {
+			t.Errorf("it.Err() = %v, want %v", got, want)
+		}
+
+		if got, want := n, 2; got != want {
+			t.Errorf("n = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("all is lost!")),
+				Header:     make(http.Header),
+				StatusCode: http.StatusInternalServerError,
+			}
+			resp.Header.Add("Content-Type", "text/plain")
+			return resp, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		it := c.SearchAll(context.Background(), nil)
+
+		if it.Next() {
+			t.Fatal("it.Next() = true, want false")
+		}
+
+		if it.Err() == nil {
+			t.Error("it.Err() = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package cmoresearch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SetRateLimit is an option to limit the rate of requests sent to the search
+// service to rps requests per second, allowing bursts of up to burst requests.
+// Searches exceeding the limit block until a request may be sent, or fail
+// right away if that would be after the deadline of their context.
+func SetRateLimit(rps float64, burst int) func(*Client) {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.rateLimiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.rateLimiter = &rateLimiter{
+			rate:   rps,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   time.Now(),
+		}
+	}
+}
+
+// SetMaxConcurrency is an option to limit the number of requests to the search
+// service in flight at the same time to n. Searches exceeding the limit block
+// until another search completes.
+func SetMaxConcurrency(n int) func(*Client) {
+	return func(c *Client) {
+		if n <= 0 {
+			c.conns = nil
+			return
+		}
+		c.conns = make(chan struct{}, n)
+	}
+}
+
+// acquireConn waits for the concurrency limit of the client, if any, adding
+// the time waited to meta. The returned function must be called when the
+// request is done.
+func (c *Client) acquireConn(ctx context.Context, meta *Meta) (func(), error) {
+	if c.conns == nil {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+
+	select {
+	case c.conns <- struct{}{}:
+		meta.Wait += time.Since(start)
+		return func() { <-c.conns }, nil
+	case <-ctx.Done():
+		meta.Wait += time.Since(start)
+		return nil, ctx.Err()
+	}
+}
+
+// waitRateLimit waits for the rate limit of the client, if any, adding the
+// time waited to meta.
+func (c *Client) waitRateLimit(ctx context.Context, meta *Meta) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+
+	d, err := c.rateLimiter.wait(ctx)
+	meta.Wait += d
+	return err
+}
+
+type limitDeadlineKey struct{}
+
+// limitDeadline returns the deadline of ctx or, if it has none, the deadline
+// of the search that started the coalesced request ctx is for.
+func limitDeadline(ctx context.Context) (time.Time, bool) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return deadline, true
+	}
+
+	deadline, ok := ctx.Value(limitDeadlineKey{}).(time.Time)
+	return deadline, ok
+}
+
+// rateLimiter is a token bucket rate limiter.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token from the bucket, returning how long to wait before
+// it may be used.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was never used.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
+
+// wait blocks until a token is available, returning the time waited.
+func (l *rateLimiter) wait(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+
+	d := l.reserve(start)
+	if d == 0 {
+		return 0, nil
+	}
+
+	if deadline, ok := limitDeadline(ctx); ok && deadline.Before(start.Add(d)) {
+		l.cancel()
+		return 0, context.DeadlineExceeded
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		l.cancel()
+		return time.Since(start), ctx.Err()
+	}
+}
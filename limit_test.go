@@ -0,0 +1,151 @@
+package cmoresearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func okTransport() mockTransport {
+	return func(r *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":0,"assets":[]}`)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	start := time.Now()
+
+	l := &rateLimiter{rate: 10, burst: 2, tokens: 2, last: start}
+
+	for n, tc := range []struct {
+		at   time.Duration
+		want time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 100 * time.Millisecond},
+		{0, 200 * time.Millisecond},
+		{time.Second, 0},
+	} {
+		if got := l.reserve(start.Add(tc.at)); got != tc.want {
+			t.Errorf("[%d] reserve = %s, want %s", n, got, tc.want)
+		}
+	}
+}
+
+func TestSearchRateLimit(t *testing.T) {
+	t.Run("Wait", func(t *testing.T) {
+		c := NewClient(
+			SetBaseURL("/"),
+			SetHTTPClient(&http.Client{Transport: okTransport()}),
+			SetRateLimit(50, 1),
+		)
+
+		first, err := c.Search(context.Background(), nil, NoCoalescing())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := first.Meta.Wait, time.Duration(0); got != want {
+			t.Errorf("first.Meta.Wait = %s, want %s", got, want)
+		}
+
+		second, err := c.Search(context.Background(), nil, NoCoalescing())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, min := second.Meta.Wait, 10*time.Millisecond; got < min {
+			t.Errorf("second.Meta.Wait = %s, want at least %s", got, min)
+		}
+	})
+
+	for _, tc := range []struct {
+		description string
+		options     []func(*http.Request)
+	}{
+		{"Deadline", []func(*http.Request){NoCoalescing()}},
+		{"DeadlineCoalesced", nil},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			c := NewClient(
+				SetBaseURL("/"),
+				SetHTTPClient(&http.Client{Transport: okTransport()}),
+				SetRateLimit(0.1, 1),
+			)
+
+			if _, err := c.Search(context.Background(), nil, tc.options...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			start := time.Now()
+
+			_, err := c.Search(ctx, nil, tc.options...)
+
+			if got, want := err, context.DeadlineExceeded; got != want {
+				t.Errorf("err = %v, want %v", got, want)
+			}
+
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Search blocked for %s, want it to fail fast", elapsed)
+			}
+		})
+	}
+}
+
+func TestSearchMaxConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+
+		return okTransport()(r)
+	}
+
+	c := NewClient(
+		SetBaseURL("/"),
+		SetHTTPClient(&http.Client{Transport: mockT}),
+		SetMaxConcurrency(2),
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Search(context.Background(), nil, NoCoalescing()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got, want := atomic.LoadInt32(&maxInFlight), int32(2); got != want {
+		t.Errorf("max requests in flight = %d, want %d", got, want)
+	}
+}
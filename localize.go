@@ -0,0 +1,352 @@
+package cmoresearch
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	languageFallbacksMu sync.RWMutex
+	languageFallbacks   = map[Language][]Language{
+		LanguageNb: {LanguageDa, LanguageSv},
+		LanguageDa: {LanguageNb, LanguageSv},
+		LanguageFi: {LanguageSv},
+	}
+)
+
+// RegisterLanguageFallbacks registers the languages to fall back to, in order,
+// when a localized field is empty in lang. If all of them are empty too, the
+// first non-empty of sv, nb, da and fi is used. Registering fallbacks for a
+// language that already has some replaces them.
+//
+// By default nb falls back to da and sv, da to nb and sv, and fi to sv.
+func RegisterLanguageFallbacks(lang Language, fallbacks ...Language) {
+	languageFallbacksMu.Lock()
+	defer languageFallbacksMu.Unlock()
+
+	m := make(map[Language][]Language, len(languageFallbacks)+1)
+	for l, f := range languageFallbacks {
+		m[l] = f
+	}
+	m[lang] = append([]Language(nil), fallbacks...)
+
+	languageFallbacks = m
+}
+
+var allLanguages = []Language{LanguageSv, LanguageNb, LanguageDa, LanguageFi}
+
+// DescriptionLength is the length of a localized description.
+type DescriptionLength int
+
+// Description lengths, from shortest to longest.
+const (
+	DescriptionTiny DescriptionLength = iota
+	DescriptionShort
+	DescriptionMedium
+	DescriptionLong
+	DescriptionExtended
+)
+
+// languageChain returns the languages to try, in order, for lang.
+func languageChain(lang Language) []Language {
+	languageFallbacksMu.RLock()
+	fallbacks := languageFallbacks[lang]
+	languageFallbacksMu.RUnlock()
+
+	chain := make([]Language, 0, 1+len(fallbacks)+len(allLanguages))
+	chain = append(chain, lang)
+	chain = append(chain, fallbacks...)
+	return append(chain, allLanguages...)
+}
+
+// localized holds the translations of a string field.
+type localized struct {
+	sv, nb, da, fi string
+}
+
+func (l localized) get(lang Language) string {
+	switch lang {
+	case LanguageSv:
+		return l.sv
+	case LanguageNb:
+		return l.nb
+	case LanguageDa:
+		return l.da
+	case LanguageFi:
+		return l.fi
+	}
+	return ""
+}
+
+// in returns the translation for lang, following the fallback chain.
+func (l localized) in(lang Language) string {
+	for _, fl := range languageChain(lang) {
+		if s := l.get(fl); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// localizedKeywords holds the translations of a keywords field.
+type localizedKeywords struct {
+	sv, nb, da, fi []Keyword
+}
+
+func (l localizedKeywords) get(lang Language) []Keyword {
+	switch lang {
+	case LanguageSv:
+		return l.sv
+	case LanguageNb:
+		return l.nb
+	case LanguageDa:
+		return l.da
+	case LanguageFi:
+		return l.fi
+	}
+	return nil
+}
+
+// in returns the translation for lang, following the fallback chain.
+func (l localizedKeywords) in(lang Language) []Keyword {
+	for _, fl := range languageChain(lang) {
+		if k := l.get(fl); len(k) > 0 {
+			return k
+		}
+	}
+	return nil
+}
+
+// Title returns the title in lang, or in a fallback language if missing.
+func (h *HitSubset) Title(lang Language) string {
+	return localized{h.TitleSv, h.TitleNb, h.TitleDa, h.TitleFi}.in(lang)
+}
+
+// Description returns the description of the given length in lang, or in a
+// fallback language if missing.
+func (h *HitSubset) Description(lang Language, length DescriptionLength) string {
+	return h.descriptions(length).in(lang)
+}
+
+func (h *HitSubset) descriptions(length DescriptionLength) localized {
+	switch length {
+	case DescriptionTiny:
+		return localized{h.DescriptionTinySv, h.DescriptionTinyNb, h.DescriptionTinyDa, h.DescriptionTinyFi}
+	case DescriptionShort:
+		return localized{h.DescriptionShortSv, h.DescriptionShortNb, h.DescriptionShortDa, h.DescriptionShortFi}
+	case DescriptionMedium:
+		return localized{h.DescriptionMediumSv, h.DescriptionMediumNb, h.DescriptionMediumDa, h.DescriptionMediumFi}
+	case DescriptionLong:
+		return localized{h.DescriptionLongSv, h.DescriptionLongNb, h.DescriptionLongDa, h.DescriptionLongFi}
+	case DescriptionExtended:
+		return localized{h.DescriptionExtendedSv, h.DescriptionExtendedNb, h.DescriptionExtendedDa, h.DescriptionExtendedFi}
+	}
+	return localized{}
+}
+
+// Keywords returns the keywords in lang, or in a fallback language if
+// missing.
+func (h *HitSubset) Keywords(lang Language) []Keyword {
+	return localizedKeywords{h.KeywordsSv, h.KeywordsNb, h.KeywordsDa, h.KeywordsFi}.in(lang)
+}
+
+// GenreDescription returns the genre description in lang, or in a
+// fallback language if missing.
+func (h *HitSubset) GenreDescription(lang Language) string {
+	return localized{h.GenreDescriptionSv, h.GenreDescriptionNb, h.GenreDescriptionDa, h.GenreDescriptionFi}.in(lang)
+}
+
+// Title returns the title in lang, or in a fallback language if missing.
+func (a *Asset) Title(lang Language) string {
+	return a.Subset().Title(lang)
+}
+
+// Description returns the description of the given length in lang, or in a
+// fallback language if missing.
+func (a *Asset) Description(lang Language, length DescriptionLength) string {
+	return a.Subset().Description(lang, length)
+}
+
+// Keywords returns the keywords in lang, or in a fallback language if
+// missing.
+func (a *Asset) Keywords(lang Language) []Keyword {
+	return a.Subset().Keywords(lang)
+}
+
+// GenreDescription returns the genre description in lang, or in a
+// fallback language if missing.
+func (a *Asset) GenreDescription(lang Language) string {
+	return a.Subset().GenreDescription(lang)
+}
+
+// Title returns the title in lang, or in a fallback language if missing.
+func (s *Series) Title(lang Language) string {
+	return s.Subset().Title(lang)
+}
+
+// Description returns the description of the given length in lang, or in a
+// fallback language if missing.
+func (s *Series) Description(lang Language, length DescriptionLength) string {
+	return s.Subset().Description(lang, length)
+}
+
+// Keywords returns the keywords in lang, or in a fallback language if
+// missing.
+func (s *Series) Keywords(lang Language) []Keyword {
+	return s.Subset().Keywords(lang)
+}
+
+// GenreDescription returns the genre description in lang, or in a
+// fallback language if missing.
+func (s *Series) GenreDescription(lang Language) string {
+	return s.Subset().GenreDescription(lang)
+}
+
+// Title returns the title in lang, or in a fallback language if missing.
+func (b *Brand) Title(lang Language) string {
+	return localized{b.TitleSv, b.TitleNb, b.TitleDa, b.TitleFi}.in(lang)
+}
+
+// Description returns the description of the given length in lang, or in a
+// fallback language if missing.
+func (b *Brand) Description(lang Language, length DescriptionLength) string {
+	return b.descriptions(length).in(lang)
+}
+
+func (b *Brand) descriptions(length DescriptionLength) localized {
+	switch length {
+	case DescriptionTiny:
+		return localized{b.DescriptionTinySv, b.DescriptionTinyNb, b.DescriptionTinyDa, b.DescriptionTinyFi}
+	case DescriptionShort:
+		return localized{b.DescriptionShortSv, b.DescriptionShortNb, b.DescriptionShortDa, b.DescriptionShortFi}
+	case DescriptionMedium:
+		return localized{b.DescriptionMediumSv, b.DescriptionMediumNb, b.DescriptionMediumDa, b.DescriptionMediumFi}
+	case DescriptionLong:
+		return localized{b.DescriptionLongSv, b.DescriptionLongNb, b.DescriptionLongDa, b.DescriptionLongFi}
+	case DescriptionExtended:
+		return localized{b.DescriptionExtendedSv, b.DescriptionExtendedNb, b.DescriptionExtendedDa, b.DescriptionExtendedFi}
+	}
+	return localized{}
+}
+
+// GenreDescription returns the genre description in lang, or in a
+// fallback language if missing.
+func (b *Brand) GenreDescription(lang Language) string {
+	return localized{b.GenreDescriptionSv, b.GenreDescriptionNb, b.GenreDescriptionDa, b.GenreDescriptionFi}.in(lang)
+}
+
+// Title returns the title in lang, or in a fallback language if missing.
+func (s *Season) Title(lang Language) string {
+	return localized{s.TitleSv, s.TitleNb, s.TitleDa, s.TitleFi}.in(lang)
+}
+
+// Description returns the description of the given length in lang, or in a
+// fallback language if missing.
+func (s *Season) Description(lang Language, length DescriptionLength) string {
+	return s.descriptions(length).in(lang)
+}
+
+func (s *Season) descriptions(length DescriptionLength) localized {
+	switch length {
+	case DescriptionTiny:
+		return localized{s.DescriptionTinySv, s.DescriptionTinyNb, s.DescriptionTinyDa, s.DescriptionTinyFi}
+	case DescriptionShort:
+		return localized{s.DescriptionShortSv, s.DescriptionShortNb, s.DescriptionShortDa, s.DescriptionShortFi}
+	case DescriptionMedium:
+		return localized{s.DescriptionMediumSv, s.DescriptionMediumNb, s.DescriptionMediumDa, s.DescriptionMediumFi}
+	case DescriptionLong:
+		return localized{s.DescriptionLongSv, s.DescriptionLongNb, s.DescriptionLongDa, s.DescriptionLongFi}
+	case DescriptionExtended:
+		return localized{s.DescriptionExtendedSv, s.DescriptionExtendedNb, s.DescriptionExtendedDa, s.DescriptionExtendedFi}
+	}
+	return localized{}
+}
+
+// GenreDescription returns the genre description in lang, or in a
+// fallback language if missing.
+func (s *Season) GenreDescription(lang Language) string {
+	return localized{s.GenreDescriptionSv, s.GenreDescriptionNb, s.GenreDescriptionDa, s.GenreDescriptionFi}.in(lang)
+}
+
+// DescriptionFitting returns the longest description in lang that is at most
+// maxChars characters long, or in a fallback language if there is none. If
+// no description fits, the shortest one is truncated at a word boundary and
+// ended with an ellipsis.
+func (h *HitSubset) DescriptionFitting(lang Language, maxChars int) string {
+	return fitDescription(h.descriptions, lang, maxChars)
+}
+
+// DescriptionFitting returns the longest description in lang that is at most
+// maxChars characters long. See HitSubset.DescriptionFitting.
+func (a *Asset) DescriptionFitting(lang Language, maxChars int) string {
+	return a.Subset().DescriptionFitting(lang, maxChars)
+}
+
+// DescriptionFitting returns the longest description in lang that is at most
+// maxChars characters long. See HitSubset.DescriptionFitting.
+func (s *Series) DescriptionFitting(lang Language, maxChars int) string {
+	return s.Subset().DescriptionFitting(lang, maxChars)
+}
+
+// DescriptionFitting returns the longest description in lang that is at most
+// maxChars characters long. See HitSubset.DescriptionFitting.
+func (b *Brand) DescriptionFitting(lang Language, maxChars int) string {
+	return fitDescription(b.descriptions, lang, maxChars)
+}
+
+// DescriptionFitting returns the longest description in lang that is at most
+// maxChars characters long. See HitSubset.DescriptionFitting.
+func (s *Season) DescriptionFitting(lang Language, maxChars int) string {
+	return fitDescription(s.descriptions, lang, maxChars)
+}
+
+func fitDescription(descriptions func(DescriptionLength) localized, lang Language, maxChars int) string {
+	if maxChars <= 0 {
+		return ""
+	}
+
+	for _, fl := range languageChain(lang) {
+		shortest := ""
+
+		for length := DescriptionExtended; length >= DescriptionTiny; length-- {
+			d := descriptions(length).get(fl)
+			if d == "" {
+				continue
+			}
+			if utf8.RuneCountInString(d) <= maxChars {
+				return d
+			}
+			shortest = d
+		}
+
+		if shortest != "" {
+			return truncate(shortest, maxChars)
+		}
+	}
+
+	return ""
+}
+
+// truncate shortens s to at most maxChars characters, cutting at the last word
+// boundary and adding an ellipsis.
+func truncate(s string, maxChars int) string {
+	runes := []rune(s)
+	if len(runes) <= maxChars {
+		return s
+	}
+
+	cut := runes[:maxChars-1]
+
+	for i := len(cut); i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = cut[:i]
+			break
+		}
+	}
+
+	return strings.TrimRightFunc(string(cut), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
@@ -0,0 +1,187 @@
+package cmoresearch
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLocalized(t *testing.T) {
+	for n, tc := range []struct {
+		l    localized
+		lang Language
+		want string
+	}{
+		{localized{"sv", "nb", "da", "fi"}, LanguageSv, "sv"},
+		{localized{"sv", "nb", "da", "fi"}, LanguageNb, "nb"},
+		{localized{"sv", "nb", "da", "fi"}, LanguageDa, "da"},
+		{localized{"sv", "nb", "da", "fi"}, LanguageFi, "fi"},
+		{localized{"sv", "", "da", "fi"}, LanguageNb, "da"},
+		{localized{"sv", "", "", "fi"}, LanguageNb, "sv"},
+		{localized{"sv", "nb", "", "fi"}, LanguageDa, "nb"},
+		{localized{"sv", "nb", "da", ""}, LanguageFi, "sv"},
+		{localized{"", "", "", "fi"}, LanguageNb, "fi"},
+		{localized{"", "nb", "", ""}, LanguageSv, "nb"},
+		{localized{"sv", "", "", ""}, "en", "sv"},
+		{localized{}, LanguageSv, ""},
+	} {
+		if got := tc.l.in(tc.lang); got != tc.want {
+			t.Errorf("[%d] in(%q) = %q, want %q", n, tc.lang, got, tc.want)
+		}
+	}
+}
+
+func TestRegisterLanguageFallbacks(t *testing.T) {
+	orig := languageFallbacks
+	defer func() { languageFallbacks = orig }()
+
+	RegisterLanguageFallbacks(LanguageNb, LanguageFi)
+
+	if got, want := orig[LanguageNb][0], LanguageDa; got != want {
+		t.Errorf("previous fallbacks changed to %q, want %q", got, want)
+	}
+
+	if got, want := (localized{"sv", "", "da", "fi"}).in(LanguageNb), "fi"; got != want {
+		t.Errorf("in(nb) = %q, want %q", got, want)
+	}
+}
+
+func TestAsset_Localized(t *testing.T) {
+	a := &Asset{
+		TitleSv:             "Solsidan",
+		TitleDa:             "Solsiden",
+		DescriptionShortSv:  "Kort",
+		DescriptionLongNb:   "Lang",
+		GenreDescriptionFi:  "Komedia",
+		KeywordsSv:          []Keyword{{NID: "komedi", Text: "Komedi"}},
+		DescriptionMediumDa: "Mellem",
+	}
+
+	if got, want := a.Title(LanguageNb), "Solsiden"; got != want {
+		t.Errorf("a.Title(nb) = %q, want %q", got, want)
+	}
+
+	if got, want := a.Description(LanguageDa, DescriptionShort), "Kort"; got != want {
+		t.Errorf("a.Description(da, short) = %q, want %q", got, want)
+	}
+
+	if got, want := a.Description(LanguageSv, DescriptionLong), "Lang"; got != want {
+		t.Errorf("a.Description(sv, long) = %q, want %q", got, want)
+	}
+
+	if got, want := a.Description(LanguageNb, DescriptionMedium), "Mellem"; got != want {
+		t.Errorf("a.Description(nb, medium) = %q, want %q", got, want)
+	}
+
+	if got, want := a.Description(LanguageSv, DescriptionExtended), ""; got != want {
+		t.Errorf("a.Description(sv, extended) = %q, want %q", got, want)
+	}
+
+	if got, want := a.GenreDescription(LanguageSv), "Komedia"; got != want {
+		t.Errorf("a.GenreDescription(sv) = %q, want %q", got, want)
+	}
+
+	if got := a.Keywords(LanguageFi); len(got) != 1 || got[0].NID != "komedi" {
+		t.Errorf("a.Keywords(fi) = %v, want [komedi]", got)
+	}
+}
+
+func TestBrandAndSeason_Localized(t *testing.T) {
+	b := &Brand{TitleSv: "Solsidan", DescriptionTinyFi: "Pieni", GenreDescriptionNb: "Komedie"}
+
+	if got, want := b.Title(LanguageFi), "Solsidan"; got != want {
+		t.Errorf("b.Title(fi) = %q, want %q", got, want)
+	}
+
+	if got, want := b.Description(LanguageFi, DescriptionTiny), "Pieni"; got != want {
+		t.Errorf("b.Description(fi, tiny) = %q, want %q", got, want)
+	}
+
+	if got, want := b.GenreDescription(LanguageDa), "Komedie"; got != want {
+		t.Errorf("b.GenreDescription(da) = %q, want %q", got, want)
+	}
+
+	s := &Season{TitleNb: "Sesong 1", DescriptionExtendedSv: "Lång", GenreDescriptionSv: "Komedi"}
+
+	if got, want := s.Title(LanguageDa), "Sesong 1"; got != want {
+		t.Errorf("s.Title(da) = %q, want %q", got, want)
+	}
+
+	if got, want := s.Description(LanguageNb, DescriptionExtended), "Lång"; got != want {
+		t.Errorf("s.Description(nb, extended) = %q, want %q", got, want)
+	}
+
+	if got, want := s.GenreDescription(LanguageFi), "Komedi"; got != want {
+		t.Errorf("s.GenreDescription(fi) = %q, want %q", got, want)
+	}
+}
+
+func TestDescriptionFitting(t *testing.T) {
+	h := &HitSubset{
+		DescriptionTinySv:     "Komedi i Saltsjöbaden.",
+		DescriptionShortSv:    "Komediserie om livet i förorten Saltsjöbaden.",
+		DescriptionLongSv:     "Komediserie om Alex och Anna som flyttar till Saltsjöbaden där de möter grannarna Ove och Mickan.",
+		DescriptionExtendedNb: "Komiserie om Alex og Anna som flytter til Saltsjöbaden.",
+		DescriptionShortNb:    "Komiserie fra Saltsjöbaden.",
+	}
+
+	for n, tc := range []struct {
+		lang     Language
+		maxChars int
+		want     string
+	}{
+		{LanguageSv, 1000, h.DescriptionLongSv},
+		{LanguageSv, 50, h.DescriptionShortSv},
+		{LanguageSv, 30, h.DescriptionTinySv},
+		{LanguageSv, 14, "Komedi i…"},
+		{LanguageSv, 8, "Komedi…"},
+		{LanguageSv, 4, "Kom…"},
+		{LanguageSv, 0, ""},
+		{LanguageNb, 100, h.DescriptionExtendedNb},
+		{LanguageNb, 30, h.DescriptionShortNb},
+		{LanguageNb, 20, "Komiserie fra…"},
+		{LanguageDa, 30, h.DescriptionShortNb},
+		{LanguageFi, 30, h.DescriptionTinySv},
+	} {
+		got := h.DescriptionFitting(tc.lang, tc.maxChars)
+
+		if got != tc.want {
+			t.Errorf("[%d] DescriptionFitting(%q, %d) = %q, want %q", n, tc.lang, tc.maxChars, got, tc.want)
+		}
+
+		if l := utf8.RuneCountInString(got); l > tc.maxChars {
+			t.Errorf("[%d] DescriptionFitting(%q, %d) is %d characters", n, tc.lang, tc.maxChars, l)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	for n, tc := range []struct {
+		s        string
+		maxChars int
+		want     string
+	}{
+		{"Kort", 10, "Kort"},
+		{"Åsa öppnar dörren", 10, "Åsa…"},
+		{"Åsa öppnar dörren", 11, "Åsa öppnar…"},
+		{"Ööööööööööööö", 5, "Öööö…"},
+		{"Hej, på dig", 6, "Hej…"},
+	} {
+		if got := truncate(tc.s, tc.maxChars); got != tc.want {
+			t.Errorf("[%d] truncate(%q, %d) = %q, want %q", n, tc.s, tc.maxChars, got, tc.want)
+		}
+	}
+}
+
+func TestBrandAndSeason_DescriptionFitting(t *testing.T) {
+	b := &Brand{DescriptionMediumSv: "Medel", DescriptionTinySv: "Liten"}
+
+	if got, want := b.DescriptionFitting(LanguageSv, 10), "Medel"; got != want {
+		t.Errorf("b.DescriptionFitting = %q, want %q", got, want)
+	}
+
+	s := &Season{DescriptionShortDa: "Anden sæson af serien"}
+
+	if got, want := s.DescriptionFitting(LanguageDa, 12), "Anden sæson…"; got != want {
+		t.Errorf("s.DescriptionFitting = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package cmoresearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// MarshalJSON encodes the response in the format used by the search service,
+// i.e. as an object with total_hits and assets. Meta is not encoded.
+func (r Response) MarshalJSON() ([]byte, error) {
+	hits := r.Hits
+	if hits == nil {
+		hits = []Hit{}
+	}
+
+	return json.Marshal(struct {
+		TotalHits int   `json:"total_hits"`
+		Hits      []Hit `json:"assets"`
+	}{r.TotalHits, hits})
+}
+
+// UnmarshalJSON decodes a response in the format used by the search service,
+// decoding each hit into the type registered for it like Search does.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	var v struct {
+		TotalHits int               `json:"total_hits"`
+		Hits      []json.RawMessage `json:"assets"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.TotalHits = v.TotalHits
+	r.Hits = nil
+
+	for _, h := range v.Hits {
+		hit, err := decodeHit(h, nil)
+		if err != nil {
+			return err
+		}
+		r.Hits = append(r.Hits, hit)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes an asset, including the fields in Extra.
+func (a *Asset) MarshalJSON() ([]byte, error) {
+	type asset Asset
+	return marshalWithExtra((*asset)(a), a.Extra)
+}
+
+// MarshalJSON encodes a series, including the fields in Extra.
+func (s *Series) MarshalJSON() ([]byte, error) {
+	type series Series
+	return marshalWithExtra((*series)(s), s.Extra)
+}
+
+// MarshalJSON encodes an unknown hit as the JSON it was decoded from.
+func (u *UnknownHit) MarshalJSON() ([]byte, error) {
+	if len(u.RawJSON) == 0 {
+		return json.Marshal(struct {
+			Type string `json:"type"`
+		}{u.Type})
+	}
+	return u.RawJSON, nil
+}
+
+// marshalWithExtra encodes v, which must encode as a JSON object, adding the
+// fields in extra.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(b[:len(b)-1])
+
+	for _, k := range keys {
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,112 @@
+package cmoresearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestResponse_JSONRoundTrip(t *testing.T) {
+	orig, err := decodeTestResponse(`{
+		"total_hits": 42,
+		"assets": [
+			{
+				"type": "episode",
+				"video_id": "123",
+				"title_sv": "Solsidan",
+				"episode_number": 3,
+				"brand": {"id": "b1", "title_sv": "Solsidan"},
+				"season": {"id": "s1", "season_number": 2},
+				"events": [{"site": "cmore.se", "start_time": "2019-03-01T12:00:00Z"}],
+				"new_field": {"nested": [1, 2, 3]}
+			},
+			{"type": "series", "brand_id": "b1", "seasons": [1, 2], "scripted": true},
+			{"type": "person", "id": "p1", "name": "Anna"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatalf("json.Marshal: unexpected error: %v", err)
+	}
+
+	var decoded Response
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: unexpected error: %v", err)
+	}
+
+	if got, want := decoded.TotalHits, 42; got != want {
+		t.Errorf("decoded.TotalHits = %d, want %d", got, want)
+	}
+
+	if got, want := len(decoded.Hits), 3; got != want {
+		t.Fatalf("len(decoded.Hits) = %d, want %d", got, want)
+	}
+
+	a, ok := decoded.Hits[0].(*Asset)
+	if !ok {
+		t.Fatalf("decoded.Hits[0] is a %T, want a %T", decoded.Hits[0], &Asset{})
+	}
+
+	if got, want := a.Season.Number, 2; got != want {
+		t.Errorf("a.Season.Number = %d, want %d", got, want)
+	}
+
+	if got, want := a.Events[0].StartTime, time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("a.Events[0].StartTime = %s, want %s", got, want)
+	}
+
+	if got, want := string(a.Extra["new_field"]), `{"nested":[1,2,3]}`; got != want {
+		t.Errorf(`a.Extra["new_field"] = %s, want %s`, got, want)
+	}
+
+	s, ok := decoded.Hits[1].(*Series)
+	if !ok {
+		t.Fatalf("decoded.Hits[1] is a %T, want a %T", decoded.Hits[1], &Series{})
+	}
+
+	if !s.Scripted || len(s.Seasons) != 2 {
+		t.Errorf("s = %+v, want scripted with 2 seasons", s)
+	}
+
+	u, ok := decoded.Hits[2].(*UnknownHit)
+	if !ok {
+		t.Fatalf("decoded.Hits[2] is a %T, want a %T", decoded.Hits[2], &UnknownHit{})
+	}
+
+	if got, want := u.Subset().ID, "p1"; got != want {
+		t.Errorf("u.Subset().ID = %q, want %q", got, want)
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("json.Marshal: unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(data, again) {
+		t.Errorf("round trip not lossless:\n%s\n%s", data, again)
+	}
+}
+
+func TestResponse_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Response{TotalHits: 0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := string(data), `{"total_hits":0,"assets":[]}`; got != want {
+		t.Errorf("json.Marshal = %s, want %s", got, want)
+	}
+}
+
+func TestResponse_UnmarshalJSON(t *testing.T) {
+	var r Response
+
+	if err := json.Unmarshal([]byte(`{"total_hits":1,"assets":[{}]}`), &r); err != ErrTypeMissing {
+		t.Errorf("err = %v, want %v", err, ErrTypeMissing)
+	}
+}
@@ -0,0 +1,84 @@
+package cmoresearch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// multiSearchWorkers is the number of searches made concurrently by
+// MultiSearch.
+const multiSearchWorkers = 8
+
+// MultiSearchMode tells MultiSearch how to handle failing searches.
+type MultiSearchMode int
+
+// Multi-search modes.
+const (
+	// MultiSearchPartial runs every search, whether others fail or not.
+	MultiSearchPartial MultiSearchMode = iota
+
+	// MultiSearchFailFast cancels the remaining searches as soon as one
+	// fails.
+	MultiSearchFailFast
+)
+
+// MultiSearchResult is the outcome of one of the searches of MultiSearch.
+type MultiSearchResult struct {
+	Response Response
+	Err      error
+}
+
+// MultiSearch performs the searches in queries concurrently, sharing ctx and
+// its deadline, and returns their outcomes by the same keys. See Search.
+//
+// In MultiSearchPartial mode the returned error is always nil, and the errors
+// of failed searches are only found among the results. In MultiSearchFailFast
+// mode the first error is also returned, and searches canceled or never made
+// because of it have the context error context.Canceled.
+func (c *Client) MultiSearch(ctx context.Context, queries map[string]url.Values, mode MultiSearchMode, options ...func(r *http.Request)) (map[string]MultiSearchResult, error) {
+	keys := make([]string, 0, len(queries))
+	for key := range queries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]MultiSearchResult, len(keys))
+	done := make([]bool, len(keys))
+
+	err := forEach(ctx, len(keys), multiSearchWorkers, func(ctx context.Context, i int) error {
+		query := make(url.Values, len(queries[keys[i]]))
+		for key, values := range queries[keys[i]] {
+			query[key] = append([]string(nil), values...)
+		}
+
+		res, err := c.Search(ctx, query, options...)
+
+		results[i] = MultiSearchResult{Response: res, Err: err}
+		done[i] = true
+
+		if mode == MultiSearchFailFast {
+			return err
+		}
+		return nil
+	})
+
+	m := make(map[string]MultiSearchResult, len(keys))
+
+	for i, key := range keys {
+		if !done[i] {
+			results[i].Err = ctx.Err()
+			if results[i].Err == nil {
+				results[i].Err = context.Canceled
+			}
+		}
+		m[key] = results[i]
+	}
+
+	if mode == MultiSearchPartial {
+		return m, nil
+	}
+
+	return m, err
+}
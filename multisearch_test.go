@@ -0,0 +1,114 @@
+package cmoresearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiSearch(t *testing.T) {
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		q := r.URL.Query()
+
+		if d, err := time.ParseDuration(q.Get("sleep")); err == nil {
+			select {
+			case <-time.After(d):
+			case <-r.Context().Done():
+				return nil, r.Context().Err()
+			}
+		}
+
+		status, body := http.StatusOK, `{"total_hits":1,"assets":[{"type":"movie","video_id":"`+q.Get("video_ids")+`"}]}`
+		if q.Get("fail") != "" {
+			status, body = http.StatusBadRequest, `{"code":400,"message":"Invalid parameters: fail"}`
+		}
+
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+			StatusCode: status,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+
+	c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+	t.Run("Partial", func(t *testing.T) {
+		results, err := c.MultiSearch(context.Background(), map[string]url.Values{
+			"a":    {"video_ids": {"1"}},
+			"b":    {"video_ids": {"2"}},
+			"fail": {"fail": {"1"}},
+		}, MultiSearchPartial)
+
+		if err != nil {
+			t.Fatalf("MultiSearch: unexpected error %v", err)
+		}
+
+		if got, want := len(results), 3; got != want {
+			t.Fatalf("len(results) = %d, want %d", got, want)
+		}
+
+		for key, want := range map[string]string{"a": "1", "b": "2"} {
+			r := results[key]
+			if r.Err != nil {
+				t.Errorf("results[%q].Err = %v", key, r.Err)
+				continue
+			}
+			if got := r.Response.Hits[0].(*Asset).VideoID; got != want {
+				t.Errorf("results[%q] VideoID = %q, want %q", key, got, want)
+			}
+		}
+
+		if _, ok := results["fail"].Err.(*APIError); !ok {
+			t.Errorf(`results["fail"].Err = %v, want *APIError`, results["fail"].Err)
+		}
+	})
+
+	t.Run("FailFast", func(t *testing.T) {
+		start := time.Now()
+
+		results, err := c.MultiSearch(context.Background(), map[string]url.Values{
+			"fail": {"fail": {"1"}},
+			"slow": {"video_ids": {"1"}, "sleep": {"10s"}},
+		}, MultiSearchFailFast)
+
+		if _, ok := err.(*APIError); !ok {
+			t.Fatalf("err = %v, want *APIError", err)
+		}
+
+		if results["slow"].Err == nil {
+			t.Error(`results["slow"].Err is nil, want canceled`)
+		}
+
+		if d := time.Since(start); d > 5*time.Second {
+			t.Errorf("MultiSearch took %s, want canceled early", d)
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		results, err := c.MultiSearch(ctx, map[string]url.Values{
+			"fast": {"video_ids": {"1"}},
+			"slow": {"video_ids": {"2"}, "sleep": {"10s"}},
+		}, MultiSearchPartial)
+
+		if err != nil {
+			t.Fatalf("MultiSearch: unexpected error %v", err)
+		}
+
+		if results["fast"].Err != nil {
+			t.Errorf(`results["fast"].Err = %v`, results["fast"].Err)
+		}
+
+		if got, want := results["slow"].Err, context.DeadlineExceeded; got != want {
+			t.Errorf(`results["slow"].Err = %v, want %v`, got, want)
+		}
+	})
+}
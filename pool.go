@@ -0,0 +1,63 @@
+package cmoresearch
+
+import (
+	"context"
+	"sync"
+)
+
+// forEach calls fn for every i in [0, n) from at most workers goroutines. It
+// returns the first error returned by fn, canceling the context given to the
+// calls still to be made or in progress.
+func forEach(ctx context.Context, n, workers int, fn func(ctx context.Context, i int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		err     error
+	)
+
+	jobs := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				if ferr := fn(ctx, i); ferr != nil {
+					errOnce.Do(func() {
+						err = ferr
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return err
+}
@@ -0,0 +1,87 @@
+package cmoresearch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	t.Run("Bounded", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			inFlight int
+			max      int
+			called   = make([]bool, 20)
+		)
+
+		err := forEach(context.Background(), len(called), 3, func(ctx context.Context, i int) error {
+			mu.Lock()
+			inFlight++
+			if inFlight > max {
+				max = inFlight
+			}
+			called[i] = true
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			return nil
+		})
+
+		if err != nil {
+			t.Fatalf("forEach: unexpected error %v", err)
+		}
+
+		if max > 3 {
+			t.Errorf("max = %d, want at most 3", max)
+		}
+
+		for i, c := range called {
+			if !c {
+				t.Errorf("fn not called for %d", i)
+			}
+		}
+	})
+
+	t.Run("FirstError", func(t *testing.T) {
+		errFailed := errors.New("failed")
+
+		var calls int32
+
+		err := forEach(context.Background(), 100, 2, func(ctx context.Context, i int) error {
+			atomic.AddInt32(&calls, 1)
+			if i == 1 {
+				return errFailed
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		if err != errFailed {
+			t.Errorf("err = %v, want %v", err, errFailed)
+		}
+
+		if n := atomic.LoadInt32(&calls); n > 4 {
+			t.Errorf("calls = %d, want canceled early", n)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := forEach(ctx, 10, 2, func(ctx context.Context, i int) error { return nil })
+
+		if err != context.Canceled {
+			t.Errorf("err = %v, want %v", err, context.Canceled)
+		}
+	})
+}
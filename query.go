@@ -0,0 +1,209 @@
+package cmoresearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Site is a site in the search service, e.g. cmore.se.
+type Site string
+
+// Sites known by the search service.
+const (
+	SiteSE Site = "cmore.se"
+	SiteNO Site = "cmore.no"
+	SiteDK Site = "cmore.dk"
+	SiteFI Site = "cmore.fi"
+)
+
+// Language is a language used for localized fields in the search service.
+type Language string
+
+// Languages known by the search service.
+const (
+	LanguageSv Language = "sv"
+	LanguageNb Language = "nb"
+	LanguageDa Language = "da"
+	LanguageFi Language = "fi"
+)
+
+// DeviceType is the type of device a search is made for.
+type DeviceType string
+
+// Device types known by the search service.
+const (
+	DeviceTypeWeb        DeviceType = "tve_web"
+	DeviceTypeMobile     DeviceType = "tve_mobile"
+	DeviceTypeTablet     DeviceType = "tve_tablet"
+	DeviceTypeSmartTV    DeviceType = "tve_smarttv"
+	DeviceTypeChromecast DeviceType = "tve_chromecast"
+	DeviceTypeAppleTV    DeviceType = "tve_appletv"
+)
+
+// Order is the sort order of search hits.
+type Order string
+
+// Sort orders known by the search service.
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// InvalidQueryError is returned when a Query contains a parameter value that
+// is not accepted by the search service.
+type InvalidQueryError struct {
+	Param string
+	Value string
+}
+
+func (e *InvalidQueryError) Error() string {
+	return fmt.Sprintf("invalid query parameter %s: %q", e.Param, e.Value)
+}
+
+// Query is a typed search query. The zero value is not usable, use NewQuery to
+// create a Query.
+type Query struct {
+	values url.Values
+}
+
+// NewQuery returns a new empty Query.
+func NewQuery() *Query {
+	return &Query{values: url.Values{}}
+}
+
+// Site sets the site parameter.
+func (q *Query) Site(site Site) *Query {
+	q.values.Set("site", string(site))
+	return q
+}
+
+// Language sets the lang parameter.
+func (q *Query) Language(lang Language) *Query {
+	q.values.Set("lang", string(lang))
+	return q
+}
+
+// DeviceType sets the device_type parameter.
+func (q *Query) DeviceType(deviceType DeviceType) *Query {
+	q.values.Set("device_type", string(deviceType))
+	return q
+}
+
+// BrandID sets the brand_id parameter.
+func (q *Query) BrandID(brandID string) *Query {
+	q.values.Set("brand_id", brandID)
+	return q
+}
+
+// Season sets the season parameter.
+func (q *Query) Season(number int) *Query {
+	q.values.Set("season", strconv.Itoa(number))
+	return q
+}
+
+// VideoIDs sets the video_ids parameter.
+func (q *Query) VideoIDs(ids ...string) *Query {
+	q.values.Set("video_ids", strings.Join(ids, ","))
+	return q
+}
+
+// SortBy sets the sort_by and order parameters.
+func (q *Query) SortBy(field string, order Order) *Query {
+	q.values.Set("sort_by", field)
+	q.values.Set("order", string(order))
+	return q
+}
+
+// Page sets the page parameter.
+func (q *Query) Page(page int) *Query {
+	q.values.Set("page", strconv.Itoa(page))
+	return q
+}
+
+// PageSize sets the page_size parameter.
+func (q *Query) PageSize(pageSize int) *Query {
+	q.values.Set("page_size", strconv.Itoa(pageSize))
+	return q
+}
+
+// Fields sets the fields parameter, limiting the fields returned for each hit.
+func (q *Query) Fields(fields ...string) *Query {
+	q.values.Set("fields", strings.Join(fields, ","))
+	return q
+}
+
+// Set sets an arbitrary parameter, for parameters without a typed setter.
+func (q *Query) Set(key, value string) *Query {
+	q.values.Set(key, value)
+	return q
+}
+
+// Values returns a copy of the query as url.Values.
+func (q *Query) Values() url.Values {
+	v := make(url.Values, len(q.values))
+	for key, values := range q.values {
+		v[key] = append([]string(nil), values...)
+	}
+	return v
+}
+
+// Validate returns an *InvalidQueryError for the first parameter with a value
+// that is not accepted by the search service.
+func (q *Query) Validate() error {
+	for _, p := range []struct {
+		param string
+		valid []string
+	}{
+		{"site", []string{string(SiteSE), string(SiteNO), string(SiteDK), string(SiteFI)}},
+		{"lang", []string{string(LanguageSv), string(LanguageNb), string(LanguageDa), string(LanguageFi)}},
+		{"device_type", []string{
+			string(DeviceTypeWeb), string(DeviceTypeMobile), string(DeviceTypeTablet),
+			string(DeviceTypeSmartTV), string(DeviceTypeChromecast), string(DeviceTypeAppleTV),
+		}},
+		{"order", []string{string(OrderAsc), string(OrderDesc)}},
+	} {
+		if _, ok := q.values[p.param]; !ok {
+			continue
+		}
+		if v := q.values.Get(p.param); !contains(p.valid, v) {
+			return &InvalidQueryError{Param: p.param, Value: v}
+		}
+	}
+
+	for _, param := range []string{"season", "page", "page_size"} {
+		if _, ok := q.values[param]; !ok {
+			continue
+		}
+		v := q.values.Get(param)
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			return &InvalidQueryError{Param: param, Value: v}
+		}
+	}
+
+	if _, ok := q.values["sort_by"]; ok && q.values.Get("sort_by") == "" {
+		return &InvalidQueryError{Param: "sort_by"}
+	}
+
+	return nil
+}
+
+// SearchQuery validates the query and performs a search with it. See Search.
+func (c *Client) SearchQuery(ctx context.Context, query *Query, options ...func(r *http.Request)) (Response, error) {
+	if err := query.Validate(); err != nil {
+		return Response{}, err
+	}
+	return c.Search(ctx, query.Values(), options...)
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
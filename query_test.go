@@ -0,0 +1,131 @@
+package cmoresearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	t.Run("Encode", func(t *testing.T) {
+		q := NewQuery().
+			Site(SiteSE).
+			Language(LanguageSv).
+			DeviceType(DeviceTypeWeb).
+			BrandID("34515").
+			Season(1).
+			SortBy("episode_number", OrderAsc).
+			Page(2).
+			PageSize(3).
+			Fields("title_sv", "episode_number")
+
+		want := "brand_id=34515&device_type=tve_web&fields=title_sv%2Cepisode_number&lang=sv&order=asc&page=2&page_size=3&season=1&site=cmore.se&sort_by=episode_number"
+
+		if got := q.Values().Encode(); got != want {
+			t.Errorf("q.Values().Encode() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("VideoIDs", func(t *testing.T) {
+		q := NewQuery().VideoIDs("2222333", "2222334")
+
+		if got, want := q.Values().Encode(), "video_ids=2222333%2C2222334"; got != want {
+			t.Errorf("q.Values().Encode() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("ValuesIsCopy", func(t *testing.T) {
+		q := NewQuery().Site(SiteSE)
+
+		v := q.Values()
+		v.Set("site", "modified")
+
+		if got, want := q.Values().Get("site"), "cmore.se"; got != want {
+			t.Errorf(`q.Values().Get("site") = %q, want %q`, got, want)
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		for n, tc := range []struct {
+			query     *Query
+			wantParam string
+		}{
+			{NewQuery(), ""},
+			{NewQuery().Site(SiteNO).Language(LanguageNb).DeviceType(DeviceTypeMobile), ""},
+			{NewQuery().SortBy("episode_number", OrderDesc).Page(1).PageSize(10), ""},
+			{NewQuery().Site("cmore.xx"), "site"},
+			{NewQuery().Language("en"), "lang"},
+			{NewQuery().DeviceType("toaster"), "device_type"},
+			{NewQuery().SortBy("episode_number", "up"), "order"},
+			{NewQuery().SortBy("", OrderAsc), "sort_by"},
+			{NewQuery().Season(0), "season"},
+			{NewQuery().Page(0), "page"},
+			{NewQuery().PageSize(-1), "page_size"},
+			{NewQuery().Set("page", "one"), "page"},
+		} {
+			err := tc.query.Validate()
+
+			if tc.wantParam == "" {
+				if err != nil {
+					t.Errorf("[%d] unexpected error: %v", n, err)
+				}
+				continue
+			}
+
+			qe, ok := err.(*InvalidQueryError)
+			if !ok {
+				t.Errorf("[%d] error is a %T (%v), want a %T", n, err, err, &InvalidQueryError{})
+				continue
+			}
+
+			if got, want := qe.Param, tc.wantParam; got != want {
+				t.Errorf("[%d] qe.Param = %q, want %q", n, got, want)
+			}
+		}
+	})
+}
+
+func TestSearchQuery(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			t.Fatal("unexpected request")
+			return nil, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		_, err := c.SearchQuery(context.Background(), NewQuery().SortBy("episode_number", "sideways"))
+
+		if _, ok := err.(*InvalidQueryError); !ok {
+			t.Fatalf("error is a %T (%v), want a %T", err, err, &InvalidQueryError{})
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		var gotQuery string
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			gotQuery = r.URL.RawQuery
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":0,"assets":[]}`)),
+				Header:     make(http.Header),
+				StatusCode: http.StatusOK,
+			}
+			resp.Header.Add("Content-Type", "application/json")
+			return resp, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		_, err := c.SearchQuery(context.Background(), NewQuery().Site(SiteSE).VideoIDs("1", "2"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := gotQuery, "site=cmore.se&video_ids=1%2C2"; got != want {
+			t.Errorf("query = %q, want %q", got, want)
+		}
+	})
+}
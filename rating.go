@@ -0,0 +1,182 @@
+package cmoresearch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrNoRating is returned when a hit has no parental rating for a country.
+var ErrNoRating = errors.New("no parental rating")
+
+// UnknownRatingError is returned when a parental rating is of an unregistered
+// system, of a system rating for another country, or has a value missing from
+// its system. See RegisterRatingSystem.
+type UnknownRatingError struct {
+	Rating ParentalRating
+}
+
+func (e *UnknownRatingError) Error() string {
+	return fmt.Sprintf("unknown parental rating %q in system %q for %q", e.Rating.Value, e.Rating.System, e.Rating.Country)
+}
+
+// RatingSystem describes a parental rating system.
+type RatingSystem struct {
+	// Country is the ISO 3166-1 alpha-2 code of the country the system
+	// rates for. Ratings of the system for other countries are unknown. If
+	// empty, the system rates for any country.
+	Country string
+
+	// Ages maps the values of the system, matched case-insensitively, to
+	// minimum ages. Values suitable for all ages map to 0.
+	Ages map[string]int
+}
+
+var (
+	swedishRatings   = RatingSystem{Country: "SE", Ages: map[string]int{"BTL": 0, "7": 7, "11": 11, "15": 15}}
+	norwegianRatings = RatingSystem{Country: "NO", Ages: map[string]int{"A": 0, "6": 6, "9": 9, "12": 12, "15": 15, "18": 18}}
+	danishRatings    = RatingSystem{Country: "DK", Ages: map[string]int{"A": 0, "7": 7, "11": 11, "15": 15, "F": 15}}
+	finnishRatings   = RatingSystem{Country: "FI", Ages: map[string]int{
+		"S": 0, "T": 0, "7": 7, "K7": 7, "K-7": 7, "12": 12, "K12": 12, "K-12": 12,
+		"16": 16, "K16": 16, "K-16": 16, "18": 18, "K18": 18, "K-18": 18,
+	}}
+)
+
+var (
+	ratingSystemsMu sync.RWMutex
+	ratingSystems   = map[string]RatingSystem{
+		"smfb":             swedishRatings,
+		"statens medieråd": swedishRatings,
+		"medietilsynet":    norwegianRatings,
+		"medierådet":       danishRatings,
+		"medieradet":       danishRatings,
+		"kavi":             finnishRatings,
+		"meku":             finnishRatings,
+	}
+)
+
+// RegisterRatingSystem registers a parental rating system by name, matched
+// case-insensitively against the system of each rating. Registering a system
+// that is already registered replaces it.
+//
+// The Swedish, Norwegian, Danish and Finnish systems are registered by
+// default.
+func RegisterRatingSystem(name string, system RatingSystem) {
+	ages := make(map[string]int, len(system.Ages))
+	for v, age := range system.Ages {
+		ages[strings.ToUpper(v)] = age
+	}
+	system.Ages = ages
+
+	ratingSystemsMu.Lock()
+	defer ratingSystemsMu.Unlock()
+
+	m := make(map[string]RatingSystem, len(ratingSystems)+1)
+	for n, s := range ratingSystems {
+		m[n] = s
+	}
+	m[strings.ToLower(strings.TrimSpace(name))] = system
+
+	ratingSystems = m
+}
+
+// MinimumAge returns the minimum age of the rating. It returns an
+// *UnknownRatingError if the system or value of the rating is unknown, or if
+// the system rates for another country.
+func (r ParentalRating) MinimumAge() (int, error) {
+	ratingSystemsMu.RLock()
+	system, ok := ratingSystems[strings.ToLower(strings.TrimSpace(r.System))]
+	ratingSystemsMu.RUnlock()
+
+	if !ok || system.Country != "" && !strings.EqualFold(strings.TrimSpace(r.Country), system.Country) {
+		return 0, &UnknownRatingError{Rating: r}
+	}
+
+	age, ok := system.Ages[strings.ToUpper(strings.TrimSpace(r.Value))]
+	if !ok {
+		return 0, &UnknownRatingError{Rating: r}
+	}
+
+	return age, nil
+}
+
+// minimumAge returns the highest minimum age of the ratings for country.
+func minimumAge(ratings []ParentalRating, country string) (int, error) {
+	var (
+		age   int
+		found bool
+	)
+
+	for _, r := range ratings {
+		if !strings.EqualFold(r.Country, country) {
+			continue
+		}
+
+		a, err := r.MinimumAge()
+		if err != nil {
+			return 0, err
+		}
+
+		if !found || a > age {
+			age, found = a, true
+		}
+	}
+
+	if !found {
+		return 0, ErrNoRating
+	}
+
+	return age, nil
+}
+
+// MinimumAge returns the minimum age for watching the asset in country, an ISO
+// 3166-1 alpha-2 code, by the strictest of its parental ratings for the
+// country. It returns ErrNoRating if the asset has no rating for the country,
+// or an *UnknownRatingError if any of its ratings for the country is unknown,
+// since an unknown rating may be stricter than the known ones.
+func (a *Asset) MinimumAge(country string) (int, error) {
+	return minimumAge(a.ParentalRatings, country)
+}
+
+// MinimumAge returns the minimum age for watching the series in country. See
+// Asset.MinimumAge.
+func (s *Series) MinimumAge(country string) (int, error) {
+	return minimumAge(s.ParentalRatings, country)
+}
+
+// UnratedHit is a hit dropped by FilterMinimumAge because its minimum age could
+// not be told.
+type UnratedHit struct {
+	Hit Hit
+
+	// Err is ErrNoRating or an *UnknownRatingError.
+	Err error
+}
+
+// FilterMinimumAge returns a copy of r without the hits with a minimum age
+// above maxAge in country. Hits whose minimum age cannot be told are dropped
+// too, and returned as unrated. See Filter.
+func (r Response) FilterMinimumAge(country string, maxAge int) (Response, []UnratedHit) {
+	var unrated []UnratedHit
+
+	filtered := r.Filter(func(h Hit) bool {
+		rated, ok := h.(interface {
+			MinimumAge(country string) (int, error)
+		})
+		if !ok {
+			unrated = append(unrated, UnratedHit{Hit: h, Err: ErrNoRating})
+			return false
+		}
+
+		age, err := rated.MinimumAge(country)
+		if err != nil {
+			unrated = append(unrated, UnratedHit{Hit: h, Err: err})
+			return false
+		}
+
+		return age <= maxAge
+	})
+
+	return filtered, unrated
+}
@@ -0,0 +1,125 @@
+package cmoresearch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParentalRating_MinimumAge(t *testing.T) {
+	for _, tc := range []struct {
+		rating ParentalRating
+		want   int
+		known  bool
+	}{
+		{ParentalRating{"SE", "SMFB", "Btl"}, 0, true},
+		{ParentalRating{"SE", "smfb", "11"}, 11, true},
+		{ParentalRating{"NO", "Medietilsynet", "9"}, 9, true},
+		{ParentalRating{"DK", "Medierådet", "F"}, 15, true},
+		{ParentalRating{"FI", "KAVI", "K-16"}, 16, true},
+		{ParentalRating{"FI", "KAVI", "S"}, 0, true},
+		{ParentalRating{"US", "MPAA", "PG-13"}, 0, false},
+		{ParentalRating{"SE", "SMFB", "13"}, 0, false},
+		{ParentalRating{"se", "SMFB", "7"}, 7, true},
+		{ParentalRating{"NO", "SMFB", "7"}, 0, false},
+	} {
+		age, err := tc.rating.MinimumAge()
+
+		if tc.known && err != nil {
+			t.Errorf("%v MinimumAge: unexpected error %v", tc.rating, err)
+			continue
+		}
+
+		if !tc.known {
+			var ure *UnknownRatingError
+			if !errors.As(err, &ure) {
+				t.Errorf("%v MinimumAge: err = %v, want *UnknownRatingError", tc.rating, err)
+			}
+			continue
+		}
+
+		if age != tc.want {
+			t.Errorf("%v MinimumAge = %d, want %d", tc.rating, age, tc.want)
+		}
+	}
+}
+
+func TestRegisterRatingSystem(t *testing.T) {
+	orig := ratingSystems
+	defer func() { ratingSystems = orig }()
+
+	RegisterRatingSystem("MPAA", RatingSystem{Country: "US", Ages: map[string]int{"G": 0, "pg-13": 13}})
+
+	if age, err := (ParentalRating{"US", "mpaa", "PG-13"}).MinimumAge(); err != nil || age != 13 {
+		t.Errorf("MinimumAge = %d, %v, want 13, <nil>", age, err)
+	}
+
+	if _, ok := orig["mpaa"]; ok {
+		t.Error("previous rating systems changed, want unchanged")
+	}
+}
+
+func TestAsset_MinimumAge(t *testing.T) {
+	a := &Asset{ParentalRatings: []ParentalRating{
+		{"SE", "SMFB", "7"},
+		{"se", "SMFB", "11"},
+		{"NO", "Medietilsynet", "6"},
+		{"NO", "Other", "X"},
+	}}
+
+	if age, err := a.MinimumAge("SE"); err != nil || age != 11 {
+		t.Errorf("a.MinimumAge(SE) = %d, %v, want 11, <nil>", age, err)
+	}
+
+	var ure *UnknownRatingError
+	if _, err := a.MinimumAge("NO"); !errors.As(err, &ure) || ure.Rating.System != "Other" {
+		t.Errorf("a.MinimumAge(NO) err = %v, want *UnknownRatingError for Other", err)
+	}
+
+	if _, err := a.MinimumAge("DK"); err != ErrNoRating {
+		t.Errorf("a.MinimumAge(DK) err = %v, want %v", err, ErrNoRating)
+	}
+}
+
+func TestResponse_FilterMinimumAge(t *testing.T) {
+	r := Response{
+		TotalHits: 6,
+		Hits: []Hit{
+			&Asset{VideoID: "kids", ParentalRatings: []ParentalRating{{"SE", "SMFB", "Btl"}}},
+			&Asset{VideoID: "adult", ParentalRatings: []ParentalRating{{"SE", "SMFB", "15"}}},
+			&Series{BrandID: "seven", ParentalRatings: []ParentalRating{{"SE", "SMFB", "7"}}},
+			&Asset{VideoID: "unknown", ParentalRatings: []ParentalRating{{"SE", "Other", "X"}}},
+			&Asset{VideoID: "partly-unknown", ParentalRatings: []ParentalRating{{"SE", "SMFB", "7"}, {"SE", "SMFB", "18"}}},
+			&UnknownHit{Type: "channel"},
+		},
+	}
+
+	filtered, unrated := r.FilterMinimumAge("SE", 7)
+
+	var ids []string
+	for _, h := range filtered.Hits {
+		ids = append(ids, h.Subset().ID)
+	}
+
+	if got, want := len(ids), 2; got != want || ids[0] != "kids" || ids[1] != "seven" {
+		t.Errorf("filtered hits = %v, want [kids seven]", ids)
+	}
+
+	if got, want := len(unrated), 3; got != want {
+		t.Fatalf("len(unrated) = %d, want %d", got, want)
+	}
+
+	for i, id := range []string{"unknown", "partly-unknown"} {
+		if got := unrated[i].Hit.Subset().ID; got != id {
+			t.Errorf("unrated[%d].Hit = %q, want %q", i, got, id)
+		}
+
+		var ure *UnknownRatingError
+		if !errors.As(unrated[i].Err, &ure) {
+			t.Errorf("unrated[%d].Err = %v, want *UnknownRatingError", i, unrated[i].Err)
+		}
+	}
+
+	if got, want := unrated[2].Err, ErrNoRating; got != want {
+		t.Errorf("unrated[2].Err = %v, want %v", got, want)
+	}
+}
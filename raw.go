@@ -0,0 +1,189 @@
+package cmoresearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	assetFields  = structFields(reflect.TypeOf(Asset{}))
+	seriesFields = structFields(reflect.TypeOf(Series{}))
+)
+
+// UnmarshalJSON decodes an asset, keeping its JSON for Raw and any fields not
+// mapped to Asset in Extra.
+func (a *Asset) UnmarshalJSON(data []byte) error {
+	type asset Asset
+
+	// Type mismatches are returned after decoding the rest of the hit, so
+	// that strict decoding can report them and carry on.
+	err := unmarshalHit(data, (*asset)(a))
+	if _, ok := err.(*json.UnmarshalTypeError); err != nil && !ok {
+		return err
+	}
+
+	extra, extraErr := extraFields(data, assetFields)
+	if extraErr != nil {
+		return extraErr
+	}
+
+	a.raw = append(json.RawMessage(nil), data...)
+	a.Extra = extra
+	a.hitSubset = nil
+
+	return err
+}
+
+// Raw returns the JSON of the hit as received from the search service.
+func (a *Asset) Raw() json.RawMessage {
+	return a.raw
+}
+
+// UnmarshalJSON decodes a series, keeping its JSON for Raw and any fields not
+// mapped to Series in Extra.
+func (s *Series) UnmarshalJSON(data []byte) error {
+	type series Series
+
+	// Type mismatches are returned after decoding the rest of the hit, so
+	// that strict decoding can report them and carry on.
+	err := unmarshalHit(data, (*series)(s))
+	if _, ok := err.(*json.UnmarshalTypeError); err != nil && !ok {
+		return err
+	}
+
+	extra, extraErr := extraFields(data, seriesFields)
+	if extraErr != nil {
+		return extraErr
+	}
+
+	s.raw = append(json.RawMessage(nil), data...)
+	s.Extra = extra
+	s.hitSubset = nil
+
+	return err
+}
+
+// Raw returns the JSON of the hit as received from the search service.
+func (s *Series) Raw() json.RawMessage {
+	return s.raw
+}
+
+// unmarshalHit is like json.Unmarshal, but also decodes the rest of the hit
+// when a time field holds a string that is not a valid time. Such fields are
+// left zero and reported as an *json.UnmarshalTypeError, like other type
+// mismatches.
+func unmarshalHit(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if _, ok := err.(*time.ParseError); !ok {
+		return err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var doc interface{}
+	if d.Decode(&doc) != nil {
+		return err
+	}
+
+	var paths []string
+	clearInvalidTimes(&paths, "", doc, reflect.TypeOf(v))
+	if len(paths) == 0 {
+		return err
+	}
+
+	cleared, merr := json.Marshal(doc)
+	if merr != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+
+	if err := json.Unmarshal(cleared, v); err != nil {
+		return err
+	}
+
+	sort.Strings(paths)
+
+	return &json.UnmarshalTypeError{Value: "string", Type: timeType, Field: paths[0]}
+}
+
+// clearInvalidTimes sets the time fields of v, decoded from JSON into type t,
+// that hold strings that are not valid times to null, appending their paths to
+// paths.
+func clearInvalidTimes(paths *[]string, path string, v interface{}, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := structFields(t)
+
+			for k, fv := range v {
+				f, ok := fields[strings.ToLower(k)]
+				if !ok {
+					continue
+				}
+
+				fieldPath := k
+				if path != "" {
+					fieldPath = path + "." + k
+				}
+
+				if s, ok := fv.(string); ok && f.Type == timeType {
+					if _, err := time.Parse(time.RFC3339, s); err != nil {
+						v[k] = nil
+						*paths = append(*paths, fieldPath)
+					}
+					continue
+				}
+
+				clearInvalidTimes(paths, fieldPath, fv, f.Type)
+			}
+		case reflect.Map:
+			for k, ev := range v {
+				clearInvalidTimes(paths, path+"."+k, ev, t.Elem())
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, ev := range v {
+				clearInvalidTimes(paths, path, ev, t.Elem())
+			}
+		}
+	}
+}
+
+// extraFields returns the fields of the JSON object in data that are not among
+// known, or nil if there are none. Like encoding/json, field names are matched
+// case-insensitively.
+func extraFields(data []byte, known map[string]reflect.StructField) (map[string]json.RawMessage, error) {
+	var extra map[string]json.RawMessage
+
+	err := scanObject(data, func(key, value []byte) bool {
+		if _, ok := known[string(key)]; ok {
+			return true
+		}
+		if _, ok := known[strings.ToLower(string(key))]; ok {
+			return true
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[string(key)] = append(json.RawMessage(nil), value...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return extra, nil
+}
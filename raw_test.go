@@ -0,0 +1,147 @@
+package cmoresearch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsset_UnmarshalJSON(t *testing.T) {
+	data := `{"type":"movie","video_id":"123","Title_SV":"Solsidan","new_field":{"a":1},"other":"x"}`
+
+	var a Asset
+	if err := json.Unmarshal([]byte(data), &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := a.VideoID, "123"; got != want {
+		t.Errorf("a.VideoID = %q, want %q", got, want)
+	}
+
+	if got, want := string(a.Raw()), data; got != want {
+		t.Errorf("a.Raw() = %s, want %s", got, want)
+	}
+
+	if got, want := len(a.Extra), 2; got != want {
+		t.Fatalf("len(a.Extra) = %d, want %d (%v)", got, want, a.Extra)
+	}
+
+	if got, want := string(a.Extra["new_field"]), `{"a":1}`; got != want {
+		t.Errorf(`a.Extra["new_field"] = %s, want %s`, got, want)
+	}
+
+	if got, want := string(a.Extra["other"]), `"x"`; got != want {
+		t.Errorf(`a.Extra["other"] = %s, want %s`, got, want)
+	}
+}
+
+func TestAsset_UnmarshalJSON_InvalidTime(t *testing.T) {
+	data := `{"type":"movie","events":[{"site":"cmore.se","start_time":"soon","end_time":"2019-03-01T12:00:00Z"}],"live_event_end":"yesterday","video_id":"123"}`
+
+	var a Asset
+	err := json.Unmarshal([]byte(data), &a)
+
+	ute, ok := err.(*json.UnmarshalTypeError)
+	if !ok {
+		t.Fatalf("err = %v, want a *json.UnmarshalTypeError", err)
+	}
+
+	if got, want := ute.Field, "events.start_time"; got != want {
+		t.Errorf("ute.Field = %q, want %q", got, want)
+	}
+
+	if got, want := a.VideoID, "123"; got != want {
+		t.Errorf("a.VideoID = %q, want %q", got, want)
+	}
+
+	if got, want := len(a.Events), 1; got != want {
+		t.Fatalf("len(a.Events) = %d, want %d", got, want)
+	}
+
+	if got, want := a.Events[0].EndTime, time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("a.Events[0].EndTime = %s, want %s", got, want)
+	}
+
+	if !a.LiveEventEnd.IsZero() || !a.Events[0].StartTime.IsZero() {
+		t.Errorf("invalid times = %s, %s, want zero", a.LiveEventEnd, a.Events[0].StartTime)
+	}
+
+	if got, want := string(a.Raw()), data; got != want {
+		t.Errorf("a.Raw() = %s, want %s", got, want)
+	}
+}
+
+func TestSeries_UnmarshalJSON(t *testing.T) {
+	t.Run("Extra", func(t *testing.T) {
+		data := `{"type":"series","brand_id":"456","seasons":[1,2],"new_field":true}`
+
+		var s Series
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := s.BrandID, "456"; got != want {
+			t.Errorf("s.BrandID = %q, want %q", got, want)
+		}
+
+		if got, want := string(s.Raw()), data; got != want {
+			t.Errorf("s.Raw() = %s, want %s", got, want)
+		}
+
+		if got, want := string(s.Extra["new_field"]), "true"; got != want || len(s.Extra) != 1 {
+			t.Errorf(`s.Extra = %v, want only new_field: %s`, s.Extra, want)
+		}
+	})
+
+	t.Run("NoExtra", func(t *testing.T) {
+		var s Series
+		if err := json.Unmarshal([]byte(`{"type":"series","brand_id":"456"}`), &s); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if s.Extra != nil {
+			t.Errorf("s.Extra = %v, want nil", s.Extra)
+		}
+	})
+}
+
+func TestMakeResponse_Raw(t *testing.T) {
+	hits := []string{
+		`{"type":"movie","video_id":"1"}`,
+		`{"type":"series","brand_id":"2"}`,
+		`{"type":"person","id":"3"}`,
+	}
+
+	res, err := decodeTestResponse(`{"total_hits":3,"assets":[` + hits[0] + `,` + hits[1] + `,` + hits[2] + `]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, h := range res.Hits {
+		r, ok := h.(interface{ Raw() json.RawMessage })
+		if !ok {
+			t.Fatalf("[%d] %T has no Raw method", i, h)
+		}
+
+		if got, want := string(r.Raw()), hits[i]; got != want {
+			t.Errorf("[%d] Raw() = %s, want %s", i, got, want)
+		}
+	}
+}
+
+// decodeTestResponse runs makeResponse on a response with the given body.
+func decodeTestResponse(body string) (Response, error) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/search", nil)
+
+	resp := &http.Response{
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		StatusCode: http.StatusOK,
+	}
+
+	return makeResponse(req, resp, false)
+}
@@ -0,0 +1,170 @@
+package cmoresearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrNotRecorded is returned by a Replayer for requests without a matching
+// recorded interaction.
+var ErrNotRecorded = errors.New("no recorded interaction")
+
+// Interaction is a request to, and response from, the search service as
+// recorded in a cassette. A cassette is a file with one JSON encoded
+// interaction per line.
+type Interaction struct {
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"request_header,omitempty"`
+	StatusCode    int         `json:"status"`
+	Header        http.Header `json:"header,omitempty"`
+	Body          string      `json:"body"`
+}
+
+// SetRecorder is an option to record every request sent to the search service,
+// and its response, as a cassette written to w. See Recorder.
+func SetRecorder(w io.Writer) func(*Client) {
+	return func(c *Client) {
+		c.recorder = w
+	}
+}
+
+// Recorder is an http.RoundTripper recording interactions as a cassette.
+type Recorder struct {
+	transport http.RoundTripper
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewRecorder returns a Recorder writing the interactions of transport to w.
+// If transport is nil, http.DefaultTransport is used.
+func NewRecorder(w io.Writer, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport, w: w}
+}
+
+// RoundTrip sends req using the underlying transport and records the
+// interaction. The response body is read in full before being returned.
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rec.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	data, err := json.Marshal(Interaction{
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		RequestHeader: req.Header,
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		Body:          string(body),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if _, err := rec.w.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper serving responses from a cassette, without
+// any network access. A request matches an interaction with the same method,
+// path and query parameters, in any order. If several interactions match,
+// they are served in the order recorded, the last one being repeated.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+}
+
+// NewReplayer returns a Replayer serving the interactions of the cassette read
+// from r.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{interactions: make(map[string][]Interaction)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var in Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %v", line, err)
+		}
+
+		req, err := http.NewRequest(in.Method, in.URL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cassette line %d: %v", line, err)
+		}
+
+		key := interactionKey(req)
+		rp.interactions[key] = append(rp.interactions[key], in)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rp, nil
+}
+
+// RoundTrip serves the recorded response matching req. An error wrapping
+// ErrNotRecorded is returned if there is none.
+func (rp *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := interactionKey(req)
+
+	rp.mu.Lock()
+	interactions := rp.interactions[key]
+	if len(interactions) == 0 {
+		rp.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+	}
+	in := interactions[0]
+	if len(interactions) > 1 {
+		rp.interactions[key] = interactions[1:]
+	}
+	rp.mu.Unlock()
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+		StatusCode:    in.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(in.Body)),
+		ContentLength: int64(len(in.Body)),
+		Request:       req,
+	}, nil
+}
+
+func interactionKey(req *http.Request) string {
+	return req.Method + " " + req.URL.Path + "?" + req.URL.Query().Encode()
+}
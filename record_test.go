@@ -0,0 +1,125 @@
+package cmoresearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var requests int
+
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		requests++
+
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":1,"assets":[{"type":"movie","video_id":"2222333"}]}`)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json; charset=utf-8")
+		return resp, nil
+	}
+
+	var cassette bytes.Buffer
+
+	c := NewClient(
+		SetBaseURL("https://example.com/"),
+		SetHTTPClient(&http.Client{Transport: mockT}),
+		SetRecorder(&cassette),
+		SetAppName("test"),
+	)
+
+	query := url.Values{"site": {"cmore.se"}, "video_ids": {"2222333"}}
+
+	recorded, err := c.Search(context.Background(), query, SetRequestID("abc"))
+	if err != nil {
+		t.Fatalf("Search: unexpected error %v", err)
+	}
+
+	if got, want := len(recorded.Hits), 1; got != want {
+		t.Fatalf("len(recorded.Hits) = %d, want %d", got, want)
+	}
+
+	if got, want := strings.Count(cassette.String(), "\n"), 1; got != want {
+		t.Fatalf("cassette has %d lines, want %d", got, want)
+	}
+
+	if !strings.Contains(cassette.String(), `"X-Request-Id":["abc"]`) {
+		t.Errorf("cassette %s lacks request header", cassette.String())
+	}
+
+	replayer, err := NewReplayer(&cassette)
+	if err != nil {
+		t.Fatalf("NewReplayer: unexpected error %v", err)
+	}
+
+	c = NewClient(
+		SetBaseURL("https://example.com/"),
+		SetHTTPClient(&http.Client{Transport: replayer}),
+		SetAppName("test"),
+	)
+
+	replayed, err := c.Search(context.Background(), url.Values{"video_ids": {"2222333"}, "site": {"cmore.se"}})
+	if err != nil {
+		t.Fatalf("Search: unexpected error %v", err)
+	}
+
+	if got, want := replayed.Hits[0].(*Asset).VideoID, "2222333"; got != want {
+		t.Errorf("VideoID = %q, want %q", got, want)
+	}
+
+	if got, want := requests, 1; got != want {
+		t.Errorf("requests = %d, want %d", got, want)
+	}
+
+	if _, err := c.Search(context.Background(), url.Values{"site": {"cmore.no"}}); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("err = %v, want %v", err, ErrNotRecorded)
+	}
+}
+
+func TestSetRecorder_SharedHTTPClient(t *testing.T) {
+	var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":0,"assets":[]}`)),
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}
+
+	hc := &http.Client{Transport: mockT}
+
+	var first, second bytes.Buffer
+
+	NewClient(SetBaseURL("/"), SetHTTPClient(hc), SetRecorder(&first))
+	c := NewClient(SetBaseURL("/"), SetHTTPClient(hc), SetRecorder(&second))
+
+	if _, ok := hc.Transport.(mockTransport); !ok {
+		t.Fatalf("hc.Transport = %T, want mockTransport", hc.Transport)
+	}
+
+	if _, err := c.Search(context.Background(), nil); err != nil {
+		t.Fatalf("Search: unexpected error %v", err)
+	}
+
+	if got, want := strings.Count(first.String(), "\n"), 0; got != want {
+		t.Errorf("first cassette has %d lines, want %d", got, want)
+	}
+
+	if got, want := strings.Count(second.String(), "\n"), 1; got != want {
+		t.Errorf("second cassette has %d lines, want %d", got, want)
+	}
+}
+
+func TestNewReplayer_Malformed(t *testing.T) {
+	if _, err := NewReplayer(strings.NewReader("{}\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("err = %v, want error on line 2", err)
+	}
+}
@@ -0,0 +1,112 @@
+package cmoresearch
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+var (
+	hitTypesMu sync.RWMutex
+	hitTypes   = map[string]func() Hit{
+		"movie":   func() Hit { return &Asset{} },
+		"episode": func() Hit { return &Asset{} },
+		"sport":   func() Hit { return &Asset{} },
+		"series":  func() Hit { return &Series{} },
+	}
+)
+
+// RegisterHitType registers a factory for hits of the given type, as found in
+// the type field of each hit. The factory must return a pointer that the hit
+// JSON can be unmarshaled into. Registering a type that is already registered
+// replaces its factory.
+//
+// Hits of the types movie, episode and sport are decoded as *Asset and hits of
+// type series as *Series by default. Hits of types without a registered
+// factory are decoded as *UnknownHit.
+//
+// Searches sharing a response, or served from the cache, get their own copies
+// of hits of registered types. Only exported fields are deep copied, so
+// unexported fields of such hits are shared and should not be changed.
+func RegisterHitType(typeName string, factory func() Hit) {
+	hitTypesMu.Lock()
+	defer hitTypesMu.Unlock()
+
+	hitTypes[typeName] = factory
+}
+
+// newHit returns a new hit for the given type, or nil if the type has no
+// registered factory.
+func newHit(typeName string) Hit {
+	hitTypesMu.RLock()
+	factory, ok := hitTypes[typeName]
+	hitTypesMu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return factory()
+}
+
+// decodeHit decodes a hit into the type registered for its type field. If
+// drift is not nil the hit is decoded strictly: schema drift is appended to
+// drift, and type mismatches are not returned as errors.
+func decodeHit(raw json.RawMessage, drift *[]SchemaDriftReport) (Hit, error) {
+	typeName, err := hitType(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if typeName == "" {
+		return nil, ErrTypeMissing
+	}
+
+	hit := newHit(typeName)
+	if hit == nil {
+		if drift != nil {
+			*drift = append(*drift, SchemaDriftReport{HitType: typeName, Kind: DriftUnknownType, Observed: "object"})
+		}
+		return &UnknownHit{Type: typeName, RawJSON: raw}, nil
+	}
+
+	err = unmarshalHit(raw, hit)
+
+	if drift != nil {
+		*drift = append(*drift, detectDrift(typeName, raw, reflect.TypeOf(hit))...)
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hit, nil
+}
+
+// UnknownHit is a hit of a type without a registered factory. It holds the
+// JSON of the hit as received from the search service.
+type UnknownHit struct {
+	hitSubset *HitSubset
+
+	Type    string
+	RawJSON json.RawMessage
+}
+
+// Raw returns the JSON of the hit as received from the search service.
+func (u *UnknownHit) Raw() json.RawMessage {
+	return u.RawJSON
+}
+
+// Subset returns the HitSubset for an *UnknownHit, decoded from its JSON.
+func (u *UnknownHit) Subset() *HitSubset {
+	if u.hitSubset != nil {
+		return u.hitSubset
+	}
+	u.hitSubset = &HitSubset{}
+	json.Unmarshal(u.RawJSON, u.hitSubset)
+	u.hitSubset.Type = u.Type
+	return u.hitSubset
+}
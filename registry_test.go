@@ -0,0 +1,91 @@
+package cmoresearch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testChannel struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+func (c *testChannel) Subset() *HitSubset {
+	return &HitSubset{ID: c.ID, Type: c.Type}
+}
+
+func TestRegisterHitType(t *testing.T) {
+	defer func() {
+		hitTypesMu.Lock()
+		delete(hitTypes, "test_channel")
+		hitTypesMu.Unlock()
+	}()
+
+	RegisterHitType("test_channel", func() Hit { return &testChannel{} })
+
+	hit, err := decodeHit(json.RawMessage(`{"type":"test_channel","id":"c1","name":"C More First"}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, ok := hit.(*testChannel)
+	if !ok {
+		t.Fatalf("hit is a %T, want a %T", hit, &testChannel{})
+	}
+
+	if got, want := c.Name, "C More First"; got != want {
+		t.Errorf("c.Name = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeHit(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		want Hit
+	}{
+		{`{"type":"movie"}`, &Asset{}},
+		{`{"type":"episode"}`, &Asset{}},
+		{`{"type":"sport"}`, &Asset{}},
+		{`{"type":"series"}`, &Series{}},
+		{`{"type":"person"}`, &UnknownHit{}},
+		{`{"Type":"series","type":"movie"}`, &Asset{}},
+	} {
+		hit, err := decodeHit(json.RawMessage(tc.raw), nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.raw, err)
+		}
+
+		if got, want := reflect.TypeOf(hit), reflect.TypeOf(tc.want); got != want {
+			t.Errorf("%s: hit is a %v, want a %v", tc.raw, got, want)
+		}
+	}
+
+	for _, raw := range []string{`{}`, `null`} {
+		if _, err := decodeHit(json.RawMessage(raw), nil); err != ErrTypeMissing {
+			t.Errorf("%s: err = %v, want %v", raw, err, ErrTypeMissing)
+		}
+	}
+}
+
+func TestUnknownHit_Subset(t *testing.T) {
+	u := &UnknownHit{
+		Type:    "person",
+		RawJSON: json.RawMessage(`{"type":"person","id":"p1","title_sv":"Anna"}`),
+	}
+
+	sub := u.Subset()
+
+	if got, want := sub.ID, "p1"; got != want {
+		t.Errorf("sub.ID = %q, want %q", got, want)
+	}
+
+	if got, want := sub.TitleSv, "Anna"; got != want {
+		t.Errorf("sub.TitleSv = %q, want %q", got, want)
+	}
+
+	if u.Subset() != sub {
+		t.Errorf("Second call to Subset returns different instance")
+	}
+}
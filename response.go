@@ -1,8 +1,10 @@
 package cmoresearch
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
+	"reflect"
 	"time"
 )
 
@@ -13,7 +15,162 @@ type Response struct {
 	Meta      Meta
 }
 
-// Hit is a search hit. It holds e.g. Asset or Series.
+// copy returns a deep copy of r, so that r can be kept e.g. in a cache while
+// the copy is handed out. See copyHit.
+func (r Response) copy() Response {
+	cp := r
+
+	if r.Hits != nil {
+		cp.Hits = make([]Hit, len(r.Hits))
+		for i, h := range r.Hits {
+			cp.Hits[i] = copyHit(h)
+		}
+	}
+
+	cp.Meta.Header = r.Meta.Header.Clone()
+
+	if r.Meta.RequestURL != nil {
+		u := *r.Meta.RequestURL
+		cp.Meta.RequestURL = &u
+	}
+
+	if r.Meta.SchemaDrift != nil {
+		cp.Meta.SchemaDrift = append([]SchemaDriftReport(nil), r.Meta.SchemaDrift...)
+	}
+
+	return cp
+}
+
+// copyHit returns a deep copy of h. Hits of types registered with
+// RegisterHitType are copied if they are pointers to structs, but only their
+// exported fields are deep copied; unexported fields are shared with h. Other
+// hits are not copied at all.
+func copyHit(h Hit) Hit {
+	switch h := h.(type) {
+	case *Asset:
+		cp := *h
+		deepCopyFields(reflect.ValueOf(&cp).Elem())
+		cp.hitSubset = nil
+		cp.raw = append(json.RawMessage(nil), h.raw...)
+		return &cp
+	case *Series:
+		cp := *h
+		deepCopyFields(reflect.ValueOf(&cp).Elem())
+		cp.hitSubset = nil
+		cp.raw = append(json.RawMessage(nil), h.raw...)
+		return &cp
+	case *UnknownHit:
+		cp := *h
+		cp.hitSubset = nil
+		cp.RawJSON = append(json.RawMessage(nil), h.RawJSON...)
+		return &cp
+	}
+
+	v := reflect.ValueOf(h)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return h
+	}
+
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	deepCopyFields(cp.Elem())
+
+	return cp.Interface().(Hit)
+}
+
+// deepCopyFields replaces the exported fields of the addressable struct v with
+// deep copies.
+func deepCopyFields(v reflect.Value) {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			continue
+		}
+		f := v.Field(i)
+		f.Set(deepCopy(f))
+	}
+}
+
+// deepCopy returns a copy of v not sharing any slices, maps or pointers with it,
+// apart from those in unexported struct fields.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(cp, v)
+		if needsDeepCopy(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				cp.Index(i).Set(deepCopy(v.Index(i)))
+			}
+		}
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return cp
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type().Elem())
+		cp.Elem().Set(deepCopy(v.Elem()))
+		return cp
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(deepCopy(v.Elem()))
+		return cp
+	case reflect.Array:
+		if !needsDeepCopy(v.Type().Elem()) {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+	case reflect.Struct:
+		if !needsDeepCopy(v.Type()) {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		deepCopyFields(cp)
+		return cp
+	}
+	return v
+}
+
+// needsDeepCopy tells whether values of type t may share memory when copied.
+func needsDeepCopy(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface:
+		return true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" && needsDeepCopy(f.Type) {
+				return true
+			}
+		}
+	case reflect.Array:
+		return needsDeepCopy(t.Elem())
+	}
+	return false
+}
+
+// Hit is a search hit. It holds e.g. Asset or Series, see RegisterHitType.
 type Hit interface {
 	Subset() *HitSubset
 }
@@ -76,11 +233,32 @@ type Meta struct {
 	StatusCode int
 	Header     http.Header
 	RequestURL *url.URL
+
+	// Attempts is the number of requests made to the search service,
+	// including retries.
+	Attempts int
+
+	// Cache tells whether the response was served from the cache, if the
+	// client is configured with one.
+	Cache CacheStatus
+
+	// Wait is the time spent waiting for the rate limit and concurrency
+	// limit of the client.
+	Wait time.Duration
+
+	// SchemaDrift holds the schema drift found in the hits of the response,
+	// if the client is configured with strict decoding.
+	SchemaDrift []SchemaDriftReport
 }
 
 // Asset is an asset hit returned by the search service.
 type Asset struct {
 	hitSubset *HitSubset
+	raw       json.RawMessage
+
+	// Extra holds the fields of the hit that are not mapped to any other
+	// field of Asset.
+	Extra map[string]json.RawMessage `json:"-"`
 
 	Arena                 string              `json:"arena"`
 	AwayTeam              Team                `json:"awayteam"`
@@ -223,6 +401,11 @@ func (a *Asset) Subset() *HitSubset {
 // Series is an series hit returned by the search service.
 type Series struct {
 	hitSubset *HitSubset
+	raw       json.RawMessage
+
+	// Extra holds the fields of the hit that are not mapped to any other
+	// field of Series.
+	Extra map[string]json.RawMessage `json:"-"`
 
 	BrandID               string              `json:"brand_id"`
 	Cinemascope           Image               `json:"cinemascope"`
@@ -1,11 +1,15 @@
 package cmoresearch
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
-// Ensure *Asset and *Series implement Hit
+// Ensure *Asset, *Series and *UnknownHit implement Hit
 var (
 	_ Hit = &Asset{}
 	_ Hit = &Series{}
+	_ Hit = &UnknownHit{}
 )
 
 func TestAsset_Subset(t *testing.T) {
@@ -73,3 +77,62 @@ func TestSeries_Subset(t *testing.T) {
 		}
 	})
 }
+
+func TestResponse_Copy(t *testing.T) {
+	type channel struct {
+		testChannel
+		Tags []string
+	}
+
+	a := &Asset{
+		VideoID:         "1",
+		Events:          []Event{{Site: "cmore.se", DeviceTypes: []string{"tve_web"}}},
+		Credits:         []Credit{{Name: "Felix Herngren"}},
+		ParentalRatings: []ParentalRating{{Country: "SE", Value: "7"}},
+		KeywordsSv:      []Keyword{{NID: "komedi"}},
+		Brand:           Brand{Country: []string{"SE"}},
+		Tags:            Tags{"mood": {"funny"}},
+		Extra:           map[string]json.RawMessage{"new": json.RawMessage(`1`)},
+	}
+	s := &Series{BrandID: "2", Events: []Event{{Site: "cmore.se"}}}
+	c := &channel{testChannel: testChannel{ID: "3"}, Tags: []string{"news"}}
+
+	r := Response{Hits: []Hit{a, s, c}, Meta: Meta{SchemaDrift: []SchemaDriftReport{{Path: "new"}}}}
+
+	cp := r.copy()
+
+	ca := cp.Hits[0].(*Asset)
+	ca.Events[0].Site = "cmore.no"
+	ca.Events[0].DeviceTypes[0] = "tve_mobile"
+	ca.Credits[0].Name = "changed"
+	ca.ParentalRatings[0].Value = "15"
+	ca.KeywordsSv[0].NID = "changed"
+	ca.Brand.Country[0] = "NO"
+	ca.Tags["mood"][0] = "sad"
+	ca.Extra["new"][0] = '2'
+
+	cp.Hits[1].(*Series).Events[0].Site = "cmore.dk"
+	cp.Hits[2].(*channel).Tags[0] = "sport"
+	cp.Meta.SchemaDrift[0].Path = "changed"
+
+	for _, tc := range []struct {
+		description string
+		got, want   string
+	}{
+		{"Events", a.Events[0].Site, "cmore.se"},
+		{"DeviceTypes", a.Events[0].DeviceTypes[0], "tve_web"},
+		{"Credits", a.Credits[0].Name, "Felix Herngren"},
+		{"ParentalRatings", a.ParentalRatings[0].Value, "7"},
+		{"Keywords", a.KeywordsSv[0].NID, "komedi"},
+		{"Brand", a.Brand.Country[0], "SE"},
+		{"Tags", a.Tags["mood"][0], "funny"},
+		{"Extra", string(a.Extra["new"]), "1"},
+		{"Series", s.Events[0].Site, "cmore.se"},
+		{"Registered", c.Tags[0], "news"},
+		{"SchemaDrift", r.Meta.SchemaDrift[0].Path, "new"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s: original changed to %q, want %q", tc.description, tc.got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package cmoresearch
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how requests to the search service are retried on
+// network errors and on HTTP 429, 502, 503 and 504 responses.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay is doubled
+	// for every following retry and jittered by up to 50%.
+	BaseDelay time.Duration
+
+	// MaxDelay, if non-zero, caps the delay between two attempts, including
+	// delays requested by the search service through Retry-After.
+	MaxDelay time.Duration
+}
+
+// SetRetryPolicy is an option to set a retry policy when creating a new
+// client. By default failed requests are not retried.
+func SetRetryPolicy(p RetryPolicy) func(*Client) {
+	return func(c *Client) {
+		c.retryPolicy = p
+	}
+}
+
+// do sends req, retrying according to the retry policy of the client and
+// subject to its rate limit and circuit breaker. The number of attempts made
+// and the time spent waiting for the rate limiter are recorded in meta.
+func (c *Client) do(req *http.Request, meta *Meta) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if err := c.waitRateLimit(ctx, meta); err != nil {
+			return nil, err
+		}
+
+		var done func(outcome)
+		if c.breaker != nil {
+			var err error
+			if done, err = c.breaker.allow(time.Now()); err != nil {
+				return nil, err
+			}
+		}
+
+		meta.Attempts = attempt
+
+		resp, err := c.httpClient.Do(req)
+
+		if done != nil {
+			done(requestOutcome(ctx, resp, err))
+		}
+
+		if attempt >= c.retryPolicy.MaxAttempts || !isRetryable(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := c.retryPolicy.delay(attempt, resp)
+
+		if resp != nil {
+			io.CopyN(ioutil.Discard, resp.Body, 64)
+			resp.Body.Close()
+		}
+
+		c.debugLogf("retrying in %s (attempt %d failed)", delay, attempt)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	var d time.Duration
+
+	if ra, ok := retryAfter(resp, time.Now()); ok {
+		d = ra
+	} else {
+		shift := attempt - 1
+		if shift > 30 {
+			shift = 30
+		}
+		d = p.BaseDelay << uint(shift)
+		if d > 0 {
+			d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+		}
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	return d
+}
+
+// retryAfter returns the delay requested by the Retry-After header of resp,
+// given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,188 @@
+package cmoresearch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	t.Run("RetryableStatuses", func(t *testing.T) {
+		for _, status := range []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		} {
+			requests := 0
+
+			var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+				requests++
+				resp := &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"total_hits":0,"assets":[]}`)),
+					Header:     make(http.Header),
+					StatusCode: http.StatusOK,
+				}
+				resp.Header.Add("Content-Type", "application/json")
+				if requests < 3 {
+					resp.StatusCode = status
+				}
+				return resp, nil
+			}
+
+			c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}), SetRetryPolicy(policy))
+
+			res, err := c.Search(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("[%d] unexpected error: %v", status, err)
+			}
+
+			if got, want := res.Meta.Attempts, 3; got != want {
+				t.Errorf("[%d] res.Meta.Attempts = %d, want %d", status, got, want)
+			}
+		}
+	})
+
+	t.Run("NetworkError", func(t *testing.T) {
+		requests := 0
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			requests++
+			return nil, errors.New("connection refused")
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}), SetRetryPolicy(policy))
+
+		res, err := c.Search(context.Background(), nil)
+		if err == nil {
+			t.Fatal("got nil, want error")
+		}
+
+		if got, want := requests, 3; got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+
+		if got, want := res.Meta.Attempts, 3; got != want {
+			t.Errorf("res.Meta.Attempts = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		requests := 0
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			requests++
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("all is lost!")),
+				Header:     make(http.Header),
+				StatusCode: http.StatusInternalServerError,
+			}
+			resp.Header.Add("Content-Type", "text/plain")
+			return resp, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}), SetRetryPolicy(policy))
+
+		res, err := c.Search(context.Background(), nil)
+		if err == nil {
+			t.Fatal("got nil, want error")
+		}
+
+		if got, want := requests, 1; got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+
+		if got, want := res.Meta.Attempts, 1; got != want {
+			t.Errorf("res.Meta.Attempts = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			cancel()
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				Header:     http.Header{"Retry-After": {"3600"}},
+				StatusCode: http.StatusServiceUnavailable,
+			}
+			return resp, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}), SetRetryPolicy(policy))
+
+		_, err := c.Search(ctx, nil)
+
+		if got, want := err, context.Canceled; got != want {
+			t.Errorf("err = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Run("Backoff", func(t *testing.T) {
+		p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+		for _, tc := range []struct {
+			attempt  int
+			min, max time.Duration
+		}{
+			{1, 50 * time.Millisecond, 100 * time.Millisecond},
+			{2, 100 * time.Millisecond, 200 * time.Millisecond},
+			{3, 200 * time.Millisecond, 400 * time.Millisecond},
+			{10, time.Second, time.Second},
+			{100, time.Second, time.Second},
+		} {
+			if d := p.delay(tc.attempt, nil); d < tc.min || d > tc.max {
+				t.Errorf("[%d] delay = %s, want between %s and %s", tc.attempt, d, tc.min, tc.max)
+			}
+		}
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		p := RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Minute}
+
+		resp := &http.Response{Header: http.Header{"Retry-After": {"10"}}}
+
+		if got, want := p.delay(1, resp), 10*time.Second; got != want {
+			t.Errorf("delay = %s, want %s", got, want)
+		}
+
+		resp.Header.Set("Retry-After", "3600")
+
+		if got, want := p.delay(1, resp), time.Minute; got != want {
+			t.Errorf("delay = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for n, tc := range []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"Fri, 01 Mar 2019 12:00:30 GMT", 30 * time.Second, true},
+		{"Fri, 01 Mar 2019 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		resp := &http.Response{Header: http.Header{"Retry-After": {tc.header}}}
+
+		got, ok := retryAfter(resp, now)
+
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("[%d] retryAfter = %s, %t, want %s, %t", n, got, ok, tc.want, tc.ok)
+		}
+	}
+}
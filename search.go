@@ -22,55 +22,125 @@ var (
 
 	// ErrInvalidBaseURL is returned if the client has been configured with an invalid base URL
 	ErrInvalidBaseURL = errors.New("invalid base URL")
+
+	errNotModified = fmt.Errorf("%d %s", http.StatusNotModified, http.StatusText(http.StatusNotModified))
 )
 
 // Search performs a search and returns the response. An error is returned if
 // there is an error while setting up or sending the request, but also if the
 // response status is not HTTP 200 OK or the response content is not JSON.
+//
+// Concurrent searches with the same query share a single request to the search
+// service, unless the NoCoalescing option is given.
 func (c *Client) Search(ctx context.Context, query url.Values, options ...func(r *http.Request)) (Response, error) {
 	req, err := c.newSearchRequest(ctx, query, options...)
 	if err != nil {
 		return Response{}, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	if c.cache != nil {
+		return c.cachedSearch(req)
+	}
+
+	return c.coalescedFetch(req)
+}
 
+// fetch sends req to the search service and makes a Response out of the HTTP
+// response.
+func (c *Client) fetch(req *http.Request) (Response, error) {
+	resp, meta, release, err := c.send(req)
 	if err != nil {
-		return Response{}, err
+		return Response{Meta: meta}, err
 	}
+	defer release()
 
-	meta := Meta{
-		StatusCode: resp.StatusCode,
-		Header:     resp.Header,
-		RequestURL: req.URL,
+	response, err := makeResponse(req, resp, c.strictDecoding != nil)
+	if err != nil {
+		return Response{Meta: meta}, err
+	}
+	meta.SchemaDrift = response.Meta.SchemaDrift
+	response.Meta = meta
+
+	if err := c.reportDrift(meta.SchemaDrift, meta.SchemaDrift); err != nil {
+		return Response{Meta: meta}, err
+	}
+
+	return response, nil
+}
+
+// send sends req to the search service and returns the HTTP response if it is
+// a JSON 200 OK response, along with its meta information. The returned
+// function must be called when done with the response body.
+func (c *Client) send(req *http.Request) (*http.Response, Meta, func(), error) {
+	meta := Meta{RequestURL: req.URL}
+
+	releaseConn, err := c.acquireConn(req.Context(), &meta)
+	if err != nil {
+		return nil, meta, nil, err
+	}
+
+	resp, err := c.do(req, &meta)
+
+	if err != nil {
+		releaseConn()
+		return nil, meta, nil, err
 	}
 
-	defer func() {
+	meta.StatusCode = resp.StatusCode
+	meta.Header = resp.Header
+
+	release := func() {
 		io.CopyN(ioutil.Discard, resp.Body, 64)
 		resp.Body.Close()
-	}()
+		releaseConn()
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		release()
+		return nil, meta, nil, errNotModified
+	}
 
 	if resp.StatusCode != http.StatusOK {
+		defer release()
+
 		if !isJSONResponse(resp) {
-			return Response{Meta: meta}, fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+			return nil, meta, nil, fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
 		}
 		var ae APIError
 		if err := json.NewDecoder(resp.Body).Decode(&ae); err != nil {
-			return Response{Meta: meta}, fmt.Errorf("%d %s; JSON response body malformed (%v)", resp.StatusCode, http.StatusText(resp.StatusCode), err)
+			return nil, meta, nil, fmt.Errorf("%d %s; JSON response body malformed (%v)", resp.StatusCode, http.StatusText(resp.StatusCode), err)
 		}
-		return Response{Meta: meta}, &ae
+		return nil, meta, nil, &ae
 	}
 
 	if !isJSONResponse(resp) {
-		return Response{Meta: meta}, ErrContentTypeNotJSON
+		release()
+		return nil, meta, nil, ErrContentTypeNotJSON
 	}
 
-	response, err := makeResponse(req, resp)
-	if err != nil {
-		return Response{Meta: meta}, err
+	return resp, meta, release, nil
+}
+
+// reportDrift passes the schema drift found to the OnDrift callback of strict
+// decoding, and returns a *SchemaDriftError with all the drift found so far if
+// strict decoding fails on drift.
+func (c *Client) reportDrift(found, all []SchemaDriftReport) error {
+	sd := c.strictDecoding
+	if sd == nil || len(found) == 0 {
+		return nil
 	}
 
-	return response, nil
+	if sd.OnDrift != nil {
+		for _, r := range found {
+			sd.OnDrift(r)
+		}
+	}
+
+	if sd.FailOnDrift {
+		return &SchemaDriftError{Reports: all}
+	}
+
+	return nil
 }
 
 func (c *Client) newSearchRequest(ctx context.Context, query url.Values, options ...func(r *http.Request)) (*http.Request, error) {
@@ -130,53 +200,35 @@ func SetRequestID(requestID string) func(*http.Request) {
 	}
 }
 
-func makeResponse(req *http.Request, resp *http.Response) (Response, error) {
+func makeResponse(req *http.Request, resp *http.Response, strict bool) (Response, error) {
 	meta := Meta{
 		StatusCode: resp.StatusCode,
 		Header:     resp.Header,
 		RequestURL: req.URL,
 	}
 
-	var v struct {
-		TotalHits int               `json:"total_hits"`
-		Hits      []json.RawMessage `json:"assets"`
-	}
+	response := Response{Meta: meta}
 
-	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
-		return Response{Meta: meta}, err
+	var drift *[]SchemaDriftReport
+	if strict {
+		drift = &response.Meta.SchemaDrift
 	}
 
-	response := Response{
-		TotalHits: v.TotalHits,
-		Meta:      meta,
-	}
+	dec := newHitDecoder(resp.Body, drift)
 
-	for _, h := range v.Hits {
-		var t struct {
-			Type string
+	for {
+		hit, err := dec.next()
+		if err == io.EOF {
+			break
 		}
-
-		if err := json.Unmarshal(h, &t); err != nil {
+		if err != nil {
+			response.TotalHits = dec.totalHits
 			return response, err
 		}
-
-		switch t.Type {
-		case "":
-			return response, ErrTypeMissing
-		case "series":
-			var series Series
-			if err := json.Unmarshal(h, &series); err != nil {
-				return response, err
-			}
-			response.Hits = append(response.Hits, &series)
-		default:
-			var asset Asset
-			if err := json.Unmarshal(h, &asset); err != nil {
-				return response, err
-			}
-			response.Hits = append(response.Hits, &asset)
-		}
+		response.Hits = append(response.Hits, hit)
 	}
 
+	response.TotalHits = dec.totalHits
+
 	return response, nil
 }
@@ -243,7 +243,7 @@ func TestMakeResponse(t *testing.T) {
 			StatusCode: http.StatusTeapot,
 		}
 
-		response, err := makeResponse(req, resp)
+		response, err := makeResponse(req, resp, false)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -264,8 +264,8 @@ func TestMakeResponse(t *testing.T) {
 			t.Errorf("response.Hits[1] is a %T, want a %T", response.Hits[1], &Series{})
 		}
 
-		if _, ok := response.Hits[2].(*Asset); !ok {
-			t.Errorf("response.Hits[2] is a %T, want a %T", response.Hits[2], &Asset{})
+		if _, ok := response.Hits[2].(*UnknownHit); !ok {
+			t.Errorf("response.Hits[2] is a %T, want a %T", response.Hits[2], &UnknownHit{})
 		}
 
 		if got, want := response.Meta.RequestURL.String(), target; got != want {
@@ -345,7 +345,7 @@ func TestMakeResponse(t *testing.T) {
 					StatusCode: http.StatusTeapot,
 				}
 
-				response, err := makeResponse(req, resp)
+				response, err := makeResponse(req, resp, false)
 				if err == nil {
 					t.Fatal("got nil, want error")
 				}
@@ -389,7 +389,7 @@ func TestMakeResponse(t *testing.T) {
 			StatusCode: http.StatusTeapot,
 		}
 
-		response, err := makeResponse(req, resp)
+		response, err := makeResponse(req, resp, false)
 		if err == nil {
 			t.Fatal("got nil, want error")
 		}
@@ -426,7 +426,7 @@ func TestMakeResponse(t *testing.T) {
 			StatusCode: http.StatusTeapot,
 		}
 
-		response, err := makeResponse(req, resp)
+		response, err := makeResponse(req, resp, false)
 		if err == nil {
 			t.Fatal("got nil, want error")
 		}
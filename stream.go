@@ -0,0 +1,164 @@
+package cmoresearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SearchStream performs a search like Search, but calls fn with each hit as it
+// is decoded while the response body is read, instead of collecting the hits
+// in the response. It stops at, and returns, the first error returned by fn.
+// The returned Response has no hits, and TotalHits is only set once every hit
+// has been read.
+//
+// SearchStream neither uses the cache of the client nor shares requests with
+// concurrent searches.
+func (c *Client) SearchStream(ctx context.Context, query url.Values, fn func(Hit) error, options ...func(r *http.Request)) (Response, error) {
+	req, err := c.newSearchRequest(ctx, query, options...)
+	if err != nil {
+		return Response{}, err
+	}
+
+	resp, meta, release, err := c.send(req)
+	if err != nil {
+		return Response{Meta: meta}, err
+	}
+	defer release()
+
+	var drift *[]SchemaDriftReport
+	if c.strictDecoding != nil {
+		drift = &meta.SchemaDrift
+	}
+
+	dec := newHitDecoder(resp.Body, drift)
+
+	for {
+		reported := len(meta.SchemaDrift)
+
+		hit, err := dec.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Response{Meta: meta}, err
+		}
+
+		if err := c.reportDrift(meta.SchemaDrift[reported:], meta.SchemaDrift); err != nil {
+			return Response{Meta: meta}, err
+		}
+
+		if err := fn(hit); err != nil {
+			return Response{Meta: meta}, err
+		}
+	}
+
+	return Response{TotalHits: dec.totalHits, Meta: meta}, nil
+}
+
+// hitDecoder decodes the hits of a search response one at a time, while the
+// response body is read.
+type hitDecoder struct {
+	dec   *json.Decoder
+	drift *[]SchemaDriftReport
+
+	totalHits int
+
+	started  bool
+	inAssets bool
+}
+
+func newHitDecoder(r io.Reader, drift *[]SchemaDriftReport) *hitDecoder {
+	return &hitDecoder{dec: json.NewDecoder(r), drift: drift}
+}
+
+// next returns the next hit, or io.EOF once the whole response has been read.
+// The total number of hits is known once next has returned io.EOF.
+func (d *hitDecoder) next() (Hit, error) {
+	for {
+		if d.inAssets {
+			if d.dec.More() {
+				var raw json.RawMessage
+				if err := d.dec.Decode(&raw); err != nil {
+					return nil, err
+				}
+				return decodeHit(raw, d.drift)
+			}
+
+			if err := d.expect(json.Delim(']')); err != nil {
+				return nil, err
+			}
+			d.inAssets = false
+			continue
+		}
+
+		if !d.started {
+			if err := d.expect(json.Delim('{')); err != nil {
+				return nil, err
+			}
+			d.started = true
+			continue
+		}
+
+		if !d.dec.More() {
+			if err := d.expect(json.Delim('}')); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+
+		key, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch k, _ := key.(string); {
+		case strings.EqualFold(k, "total_hits"):
+			if err := d.dec.Decode(&d.totalHits); err != nil {
+				return nil, err
+			}
+		case strings.EqualFold(k, "assets"):
+			tok, err := d.token()
+			if err != nil {
+				return nil, err
+			}
+
+			switch tok {
+			case json.Delim('['):
+				d.inAssets = true
+			case nil:
+			default:
+				return nil, fmt.Errorf("json: assets is %v, not an array", tok)
+			}
+		default:
+			var skip json.RawMessage
+			if err := d.dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// token returns the next token, treating the end of the input as unexpected.
+func (d *hitDecoder) token() (json.Token, error) {
+	tok, err := d.dec.Token()
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return tok, err
+}
+
+func (d *hitDecoder) expect(delim json.Delim) error {
+	tok, err := d.token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("json: unexpected %v, want %v", tok, delim)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package cmoresearch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHitDecoder(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		body        string
+		ids         []string
+		totalHits   int
+		err         string
+	}{
+		{"Empty", `{"total_hits":0,"assets":[]}`, nil, 0, ""},
+		{"NullAssets", `{"total_hits":0,"assets":null}`, nil, 0, ""},
+		{"TotalHitsLast", `{"assets":[{"type":"movie","video_id":"1"},{"type":"series","brand_id":"2"}],"total_hits":7}`, []string{"1", "2"}, 7, ""},
+		{"OtherKeys", `{"took":{"ms":[1,2]},"total_hits":1,"assets":[{"type":"movie","video_id":"1"}],"status":"ok"}`, []string{"1"}, 1, ""},
+		{"Truncated", `{"total_hits":2,"assets":[{"type":"movie","video_id":"1"},{"type":"mo`, []string{"1"}, 2, "unexpected EOF"},
+		{"TruncatedAfterHit", `{"total_hits":1,"assets":[{"type":"movie","video_id":"1"}`, []string{"1"}, 1, "unexpected end of JSON input"},
+		{"NotObject", `[]`, nil, 0, "json: unexpected [, want {"},
+		{"AssetsNotArray", `{"assets":{}}`, nil, 0, "json: assets is {, not an array"},
+		{"TypeMissing", `{"assets":[{"video_id":"1"}]}`, nil, 0, ErrTypeMissing.Error()},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			dec := newHitDecoder(strings.NewReader(tc.body), nil)
+
+			var (
+				ids []string
+				err error
+			)
+
+			for {
+				var hit Hit
+				if hit, err = dec.next(); err != nil {
+					break
+				}
+				ids = append(ids, hit.Subset().ID)
+			}
+
+			if err == io.EOF {
+				err = nil
+			}
+
+			if tc.err == "" && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if tc.err != "" && (err == nil || err.Error() != tc.err) {
+				t.Fatalf("err = %v, want %q", err, tc.err)
+			}
+
+			if got, want := strings.Join(ids, ","), strings.Join(tc.ids, ","); got != want {
+				t.Errorf("ids = %q, want %q", got, want)
+			}
+
+			if got, want := dec.totalHits, tc.totalHits; got != want {
+				t.Errorf("dec.totalHits = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+// pipeTransport responds with the JSON written to the returned writer.
+func pipeTransport() (mockTransport, *io.PipeWriter) {
+	pr, pw := io.Pipe()
+
+	return func(r *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			Body:       pr,
+			Header:     make(http.Header),
+			StatusCode: http.StatusOK,
+		}
+		resp.Header.Add("Content-Type", "application/json")
+		return resp, nil
+	}, pw
+}
+
+func TestSearchStream(t *testing.T) {
+	t.Run("BeforeBodyRead", func(t *testing.T) {
+		mockT, pw := pipeTransport()
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		hits := make(chan Hit)
+
+		go func() {
+			io.WriteString(pw, `{"assets":[{"type":"movie","video_id":"1"},`)
+
+			select {
+			case hit := <-hits:
+				if got, want := hit.Subset().ID, "1"; got != want {
+					t.Errorf("first hit ID = %q, want %q", got, want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Error("first hit not decoded before the body was read")
+			}
+
+			io.WriteString(pw, `{"type":"movie","video_id":"2"}],"total_hits":2}`)
+			pw.Close()
+
+			<-hits
+		}()
+
+		res, err := c.SearchStream(context.Background(), url.Values{}, func(h Hit) error {
+			hits <- h
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("SearchStream: unexpected error %v", err)
+		}
+
+		if got, want := res.TotalHits, 2; got != want {
+			t.Errorf("res.TotalHits = %d, want %d", got, want)
+		}
+
+		if got, want := len(res.Hits), 0; got != want {
+			t.Errorf("len(res.Hits) = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("StopOnError", func(t *testing.T) {
+		body := `{"total_hits":3,"assets":[{"type":"movie","video_id":"1"},{"type":"movie","video_id":"2"},{"type":"movie","video_id":"3"}]}`
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+				StatusCode: http.StatusOK,
+			}
+			resp.Header.Add("Content-Type", "application/json")
+			return resp, nil
+		}
+
+		c := NewClient(SetBaseURL("/"), SetHTTPClient(&http.Client{Transport: mockT}))
+
+		errStop := errors.New("stop")
+
+		var calls int
+
+		_, err := c.SearchStream(context.Background(), url.Values{}, func(h Hit) error {
+			calls++
+			if h.Subset().ID == "2" {
+				return errStop
+			}
+			return nil
+		})
+
+		if err != errStop {
+			t.Errorf("err = %v, want %v", err, errStop)
+		}
+
+		if got, want := calls, 2; got != want {
+			t.Errorf("calls = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("FailOnDrift", func(t *testing.T) {
+		body := `{"total_hits":2,"assets":[{"type":"movie","video_id":"1"},{"type":"movie","video_id":"2","new_field":1}]}`
+
+		var mockT mockTransport = func(r *http.Request) (*http.Response, error) {
+			resp := &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+				StatusCode: http.StatusOK,
+			}
+			resp.Header.Add("Content-Type", "application/json")
+			return resp, nil
+		}
+
+		c := NewClient(
+			SetBaseURL("/"),
+			SetHTTPClient(&http.Client{Transport: mockT}),
+			SetStrictDecoding(StrictDecoding{FailOnDrift: true}),
+		)
+
+		var calls int
+
+		res, err := c.SearchStream(context.Background(), url.Values{}, func(h Hit) error {
+			calls++
+			return nil
+		})
+
+		if _, ok := err.(*SchemaDriftError); !ok {
+			t.Fatalf("err = %v, want *SchemaDriftError", err)
+		}
+
+		if got, want := calls, 1; got != want {
+			t.Errorf("calls = %d, want %d", got, want)
+		}
+
+		if got, want := len(res.Meta.SchemaDrift), 1; got != want {
+			t.Errorf("len(res.Meta.SchemaDrift) = %d, want %d", got, want)
+		}
+	})
+}